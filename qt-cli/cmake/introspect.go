@@ -0,0 +1,202 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmake
+
+import (
+	"slices"
+	"strings"
+)
+
+type QmlModule struct {
+	Target   string
+	URI      string
+	Version  string
+	QmlFiles []string
+	Sources  []string
+}
+
+// keywords followed by a value which is not a source file,
+// e.g., target_sources(foo PUBLIC FILE_SET HEADERS TYPE HEADERS ...)
+var sourceValueKeywords = []string{
+	"FILE_SET", "TYPE", "OUTPUT_NAME",
+}
+
+// keywords followed by values which are not source files
+var nonSourceSectionKeywords = []string{
+	"BASE_DIRS",
+}
+
+// returns directories given to add_subdirectory()
+func (l *ListFile) Subdirectories() []string {
+	all := []string{}
+	for _, command := range l.FindCommands("add_subdirectory") {
+		if dir := command.FirstArg(); len(dir) != 0 {
+			all = append(all, dir)
+		}
+	}
+
+	return all
+}
+
+// returns "executable" or "library", or an empty string if the target
+// is not defined in this file
+func (l *ListFile) TargetType(target string) string {
+	command, found := l.findTargetCommand(target)
+	if !found {
+		return ""
+	}
+
+	if strings.HasSuffix(strings.ToLower(command.Name), "_executable") {
+		return "executable"
+	}
+
+	return "library"
+}
+
+// returns source files of the target listed in this file, as written
+func (l *ListFile) TargetSources(target string) []string {
+	all := []string{}
+
+	commands := l.FindCommands(targetCommandNames...)
+	commands = append(commands, l.FindCommands("target_sources")...)
+	for _, command := range commands {
+		if command.FirstArg() != target || !isRealTarget(command) {
+			continue
+		}
+
+		for _, value := range sourceArgs(command) {
+			if !slices.Contains(all, value) {
+				all = append(all, value)
+			}
+		}
+	}
+
+	for _, module := range l.QmlModules() {
+		if module.Target != target {
+			continue
+		}
+
+		for _, value := range append(module.Sources, module.QmlFiles...) {
+			if !slices.Contains(all, value) {
+				all = append(all, value)
+			}
+		}
+	}
+
+	return all
+}
+
+// returns modules defined by qt_add_qml_module() in this file
+func (l *ListFile) QmlModules() []QmlModule {
+	all := []QmlModule{}
+
+	for _, command := range l.FindCommands(qmlModuleCommandNames...) {
+		module := QmlModule{
+			Target:   command.FirstArg(),
+			QmlFiles: sectionValues(command, "QML_FILES"),
+			Sources:  sectionValues(command, "SOURCES"),
+		}
+
+		if values := sectionValues(command, "URI"); len(values) != 0 {
+			module.URI = values[0]
+		}
+
+		if values := sectionValues(command, "VERSION"); len(values) != 0 {
+			module.Version = values[0]
+		}
+
+		all = append(all, module)
+	}
+
+	return all
+}
+
+// returns the major version of Qt found by find_package(), or linked
+// to a target, or 0 if unknown.
+// for find_package(QT NAMES Qt6 Qt5 ...), the first name is preferred.
+func (l *ListFile) QtMajorVersion() int {
+	for _, command := range l.FindCommands("find_package") {
+		if !isQtPackageName(command.FirstArg()) {
+			continue
+		}
+
+		name := command.FirstArg()
+		if names := sectionValues(command, "NAMES"); len(names) != 0 {
+			name = names[0]
+		}
+
+		if version := qtMajorVersionOf(name); version != 0 {
+			return version
+		}
+	}
+
+	for _, command := range l.FindCommands("target_link_libraries") {
+		for _, arg := range command.Args[1:] {
+			name, _, found := strings.Cut(arg.Value, "::")
+			if !found {
+				continue
+			}
+
+			if version := qtMajorVersionOf(name); version != 0 {
+				return version
+			}
+		}
+	}
+
+	return 0
+}
+
+func qtMajorVersionOf(packageName string) int {
+	switch packageName {
+	case "Qt6":
+		return 6
+
+	case "Qt5":
+		return 5
+	}
+
+	return 0
+}
+
+// returns non-keyword arguments after the target name,
+// skipping values of keywords which do not name source files
+func sourceArgs(command Command) []string {
+	all := []string{}
+	skipNext := false
+	inSkippedSection := false
+
+	for _, arg := range command.Args[1:] {
+		if arg.IsKeyword() {
+			skipNext = slices.Contains(sourceValueKeywords, arg.Value)
+			inSkippedSection = slices.Contains(
+				nonSourceSectionKeywords, arg.Value)
+			continue
+		}
+
+		if skipNext {
+			skipNext = false
+			continue
+		}
+
+		if !inSkippedSection {
+			all = append(all, arg.Value)
+		}
+	}
+
+	return all
+}
+
+func sectionValues(command Command, keyword string) []string {
+	section := findSection(command, keyword)
+	if len(section) == 0 {
+		return []string{}
+	}
+
+	all := []string{}
+	for _, arg := range section[1:] {
+		all = append(all, arg.Value)
+	}
+
+	return all
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmake
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"qtcli/util"
+	"strings"
+)
+
+const ListFileName = "CMakeLists.txt"
+
+type ListFile struct {
+	Path     string
+	Source   string
+	Commands []Command
+
+	original string
+}
+
+// walks up from the given directory until a CMakeLists.txt is found
+func FindListFile(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, ListFileName)
+		if stat, err := os.Stat(path); err == nil && stat.Mode().IsRegular() {
+			return path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf(
+				"cannot find %v from %v upward", ListFileName, startDir)
+		}
+
+		dir = parent
+	}
+}
+
+func ReadListFile(path string) (*ListFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &ListFile{Path: path, original: string(raw)}
+	if err := l.setSource(string(raw)); err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+
+	return l, nil
+}
+
+func (l *ListFile) Write() error {
+	if err := os.WriteFile(l.Path, []byte(l.Source), 0666); err != nil {
+		return err
+	}
+
+	l.original = l.Source
+	return nil
+}
+
+func (l *ListFile) Dir() string {
+	return filepath.Dir(l.Path)
+}
+
+// returns true if the source differs from the one read
+func (l *ListFile) IsModified() bool {
+	return l.Source != l.original
+}
+
+// returns changes made since the file was read, as a unified diff
+func (l *ListFile) Diff() string {
+	return util.UnifiedDiff(l.Path, l.original, l.Source)
+}
+
+// returns commands with the given name, case-insensitively
+func (l *ListFile) FindCommands(names ...string) []Command {
+	all := []Command{}
+	for _, command := range l.Commands {
+		for _, name := range names {
+			if command.Is(name) {
+				all = append(all, command)
+				break
+			}
+		}
+	}
+
+	return all
+}
+
+func (l *ListFile) setSource(source string) error {
+	commands, err := Parse(source)
+	if err != nil {
+		return err
+	}
+
+	l.Source = source
+	l.Commands = commands
+	return nil
+}
+
+func (l *ListFile) insert(offset int, text string) error {
+	return l.setSource(l.Source[:offset] + text + l.Source[offset:])
+}
+
+// returns the position right after the given argument where a new argument
+// can be inserted, as well as the separator to place in front of it.
+// the style of the line is followed: if the argument begins its line,
+// a new line is started with the same indentation, keeping a trailing
+// comment where it is. otherwise, the line is extended.
+func (l *ListFile) findInsertion(command Command, after Argument) (int, string) {
+	if !l.isMultiLine(command) {
+		return after.End, " "
+	}
+
+	if after.Line != command.Line && !l.startsLine(after.Start) {
+		return after.End, " "
+	}
+
+	offset := after.End
+	for offset < len(l.Source) &&
+		(l.Source[offset] == ' ' || l.Source[offset] == '\t') {
+		offset++
+	}
+
+	if offset < len(l.Source) && l.Source[offset] == '#' &&
+		!strings.HasPrefix(l.Source[offset:], "#[") {
+		for offset < len(l.Source) && l.Source[offset] != '\n' {
+			offset++
+		}
+	} else {
+		offset = after.End
+	}
+
+	indent := l.findArgIndent(command)
+	if after.Line != command.Line {
+		indent = l.lineIndent(after.Start)
+	}
+
+	return offset, "\n" + indent
+}
+
+func (l *ListFile) isMultiLine(command Command) bool {
+	if len(command.Args) == 0 {
+		return false
+	}
+
+	last := command.Args[len(command.Args)-1]
+	return last.Line != command.Line
+}
+
+func (l *ListFile) findArgIndent(command Command) string {
+	for _, arg := range command.Args {
+		if arg.Line != command.Line {
+			return l.lineIndent(arg.Start)
+		}
+	}
+
+	return l.lineIndent(command.Start) + "    "
+}
+
+func (l *ListFile) startsLine(offset int) bool {
+	start := strings.LastIndexByte(l.Source[:offset], '\n') + 1
+	return len(strings.TrimLeft(l.Source[start:offset], " \t")) == 0
+}
+
+func (l *ListFile) lineIndent(offset int) string {
+	start := strings.LastIndexByte(l.Source[:offset], '\n') + 1
+	end := start
+	for end < len(l.Source) &&
+		(l.Source[end] == ' ' || l.Source[end] == '\t') {
+		end++
+	}
+
+	return l.Source[start:end]
+}
@@ -0,0 +1,298 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmake
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// possible prefixes of imported Qt targets, e.g., Qt6::Core
+var qtTargetPrefixes = []string{
+	"Qt::", "Qt6::", "Qt5::", "Qt${QT_VERSION_MAJOR}::",
+}
+
+// returns the first find_package() call of Qt in this file,
+// e.g., find_package(Qt6 REQUIRED COMPONENTS Core).
+// find_package(QT NAMES Qt6 Qt5) is not the one.
+func (l *ListFile) FindQtPackage() (Command, bool) {
+	for _, command := range l.FindCommands("find_package") {
+		name := command.FirstArg()
+		if !isQtPackageName(name) {
+			continue
+		}
+
+		if containsArg(command, "NAMES") {
+			continue
+		}
+
+		return command, true
+	}
+
+	return Command{}, false
+}
+
+// returns components listed in find_package(Qt6 ...)
+func QtComponents(command Command) []string {
+	all := []string{}
+	inList := false
+
+	// a version right after the name is skipped as well
+	for _, arg := range command.Args[1:] {
+		if arg.IsKeyword() {
+			switch arg.Value {
+			case "REQUIRED", "COMPONENTS", "OPTIONAL_COMPONENTS":
+				inList = true
+			default:
+				inList = false
+			}
+			continue
+		}
+
+		if inList {
+			all = append(all, arg.Value)
+		}
+	}
+
+	return all
+}
+
+// walks up from the directory of this file until a CMakeLists.txt
+// with find_package() of Qt is found
+func FindQtPackageListFile(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, ListFileName)
+		if _, err := os.Stat(path); err == nil {
+			l, err := ReadListFile(path)
+			if err != nil {
+				return "", err
+			}
+
+			if _, found := l.FindQtPackage(); found {
+				return path, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf(
+				"cannot find find_package() of Qt from %v upward", startDir)
+		}
+
+		dir = parent
+	}
+}
+
+// adds missing components to find_package(Qt6 ...) and returns them.
+// if there is no such call, a new one is placed before the first target.
+func (l *ListFile) AddQtComponents(components []string) ([]string, error) {
+	added := []string{}
+
+	command, found := l.FindQtPackage()
+	if !found {
+		if len(components) == 0 {
+			return added, nil
+		}
+
+		offset := len(l.Source)
+		if targets := l.FindCommands(targetCommandNames...); len(targets) != 0 {
+			offset = targets[0].Start
+		}
+
+		text := fmt.Sprintf("find_package(Qt6 REQUIRED COMPONENTS %v)\n\n",
+			strings.Join(components, " "))
+		if err := l.insert(offset, text); err != nil {
+			return added, err
+		}
+
+		return components, nil
+	}
+
+	for _, component := range components {
+		command, _ = l.FindQtPackage()
+		if slices.Contains(QtComponents(command), component) {
+			continue
+		}
+
+		// e.g., find_package(Qt6 REQUIRED Core)
+		section := findSection(command, "COMPONENTS")
+		if len(section) == 0 {
+			section = findSection(command, "REQUIRED")
+		}
+
+		var err error
+		if len(section) < 2 {
+			last := command.Args[len(command.Args)-1]
+			err = l.insertArgs(
+				command, last, []string{"COMPONENTS", component})
+		} else {
+			err = l.insertArgs(
+				command, section[len(section)-1], []string{component})
+		}
+
+		if err != nil {
+			return added, err
+		}
+
+		added = append(added, component)
+	}
+
+	return added, nil
+}
+
+// links the given components to the target and returns added link items,
+// e.g., {"Widgets"} -> {"Qt6::Widgets"}
+func (l *ListFile) LinkQtModules(
+	target string,
+	components []string,
+) ([]string, error) {
+	added := []string{}
+	prefix := l.findQtTargetPrefix(target)
+
+	for _, component := range components {
+		if l.isQtModuleLinked(target, component) {
+			continue
+		}
+
+		item := prefix + component
+		if err := l.addLinkItem(target, item); err != nil {
+			return added, err
+		}
+
+		added = append(added, item)
+	}
+
+	return added, nil
+}
+
+// returns Qt components linked to the target, e.g., {"Core", "Quick"}
+func (l *ListFile) LinkedQtModules(target string) []string {
+	all := []string{}
+
+	for _, command := range l.findLinkCommands(target) {
+		for _, arg := range command.Args[1:] {
+			for _, prefix := range qtTargetPrefixes {
+				if strings.HasPrefix(arg.Value, prefix) {
+					component := strings.TrimPrefix(arg.Value, prefix)
+					if !slices.Contains(all, component) {
+						all = append(all, component)
+					}
+				}
+			}
+		}
+	}
+
+	return all
+}
+
+func (l *ListFile) isQtModuleLinked(target string, component string) bool {
+	return slices.Contains(l.LinkedQtModules(target), component)
+}
+
+func (l *ListFile) addLinkItem(target string, item string) error {
+	commands := l.findLinkCommands(target)
+
+	if len(commands) == 0 {
+		command, found := l.findTargetCommand(target)
+		if !found {
+			return fmt.Errorf(
+				"cannot find where to link libraries to '%v' in %v",
+				target, l.Path)
+		}
+
+		// right after the line the target is defined
+		offset := command.End
+		if end := strings.IndexByte(l.Source[offset:], '\n'); end >= 0 {
+			offset += end + 1
+		} else {
+			offset = len(l.Source)
+		}
+
+		text := fmt.Sprintf(
+			"\ntarget_link_libraries(%v PRIVATE %v)\n", target, item)
+		return l.insert(offset, text)
+	}
+
+	command := commands[len(commands)-1]
+	section := findSection(command, "PRIVATE")
+	if len(section) != 0 {
+		return l.insertArgs(command, section[len(section)-1], []string{item})
+	}
+
+	last := command.Args[len(command.Args)-1]
+	if len(command.Args) == 1 {
+		return l.insertArgs(command, last, []string{"PRIVATE", item})
+	}
+
+	return l.insertArgs(command, last, []string{item})
+}
+
+func (l *ListFile) findLinkCommands(target string) []Command {
+	all := []Command{}
+	for _, command := range l.FindCommands("target_link_libraries") {
+		if command.FirstArg() == target {
+			all = append(all, command)
+		}
+	}
+
+	return all
+}
+
+// follows the style of already linked Qt modules, otherwise
+// derives it from find_package(), e.g., Qt6:: or Qt${QT_VERSION_MAJOR}::
+func (l *ListFile) findQtTargetPrefix(target string) string {
+	for _, command := range l.findLinkCommands(target) {
+		for _, arg := range command.Args[1:] {
+			for _, prefix := range qtTargetPrefixes {
+				if strings.HasPrefix(arg.Value, prefix) {
+					return prefix
+				}
+			}
+		}
+	}
+
+	if command, found := l.FindQtPackage(); found {
+		return command.FirstArg() + "::"
+	}
+
+	return "Qt6::"
+}
+
+// returns the component name of a Qt module given in any of the common
+// forms, e.g., Network, QtNetwork, Qt6::Network or Qt6Network -> Network
+func QtComponentName(name string) string {
+	for _, prefix := range qtTargetPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix)
+		}
+	}
+
+	for _, prefix := range []string{"Qt6", "Qt5", "Qt"} {
+		rest := strings.TrimPrefix(name, prefix)
+		if len(rest) != 0 && len(rest) != len(name) &&
+			unicode.IsUpper(rune(rest[0])) {
+			return rest
+		}
+	}
+
+	return name
+}
+
+func isQtPackageName(name string) bool {
+	switch name {
+	case "Qt", "Qt5", "Qt6", "Qt${QT_VERSION_MAJOR}":
+		return true
+	}
+
+	return false
+}
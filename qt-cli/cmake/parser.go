@@ -0,0 +1,299 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// note,
+// offsets point into the original source so that
+// edits can be made without touching anything around them
+type Command struct {
+	Name  string
+	Args  []Argument
+	Start int
+	End   int
+	Line  int
+}
+
+type Argument struct {
+	Value  string
+	Start  int
+	End    int
+	Line   int
+	Quoted bool
+}
+
+func (c Command) Is(name string) bool {
+	return strings.EqualFold(c.Name, name)
+}
+
+func (c Command) FirstArg() string {
+	if len(c.Args) == 0 {
+		return ""
+	}
+
+	return c.Args[0].Value
+}
+
+// e.g., STATIC, PRIVATE, QML_FILES
+func (a Argument) IsKeyword() bool {
+	if a.Quoted || len(a.Value) == 0 {
+		return false
+	}
+
+	for i, r := range a.Value {
+		switch {
+		case r >= 'A' && r <= 'Z':
+		case r == '_' && i != 0:
+		case r >= '0' && r <= '9' && i != 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func Parse(source string) ([]Command, error) {
+	p := parser{source: source, line: 1}
+	return p.parseFile()
+}
+
+type parser struct {
+	source string
+	pos    int
+	line   int
+}
+
+func (p *parser) parseFile() ([]Command, error) {
+	all := []Command{}
+
+	for p.pos < len(p.source) {
+		c := p.source[p.pos]
+
+		switch {
+		case c == '\n':
+			p.line++
+			p.pos++
+
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+
+		case c == '#':
+			if err := p.skipComment(); err != nil {
+				return []Command{}, err
+			}
+
+		case isIdentifierStart(c):
+			command, err := p.parseCommand()
+			if err != nil {
+				return []Command{}, err
+			}
+
+			all = append(all, command)
+
+		default:
+			return []Command{}, p.errorf("unexpected character '%c'", c)
+		}
+	}
+
+	return all, nil
+}
+
+func (p *parser) parseCommand() (Command, error) {
+	command := Command{Start: p.pos, Line: p.line}
+
+	for p.pos < len(p.source) && isIdentifierChar(p.source[p.pos]) {
+		p.pos++
+	}
+
+	command.Name = p.source[command.Start:p.pos]
+
+	for p.pos < len(p.source) &&
+		(p.source[p.pos] == ' ' || p.source[p.pos] == '\t') {
+		p.pos++
+	}
+
+	if p.pos >= len(p.source) || p.source[p.pos] != '(' {
+		return Command{}, p.errorf("expected '(' after %v", command.Name)
+	}
+
+	p.pos++
+	depth := 1
+
+	for p.pos < len(p.source) {
+		c := p.source[p.pos]
+
+		switch {
+		case c == '\n':
+			p.line++
+			p.pos++
+
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+
+		case c == '#':
+			if err := p.skipComment(); err != nil {
+				return Command{}, err
+			}
+
+		case c == '(':
+			depth++
+			p.pos++
+
+		case c == ')':
+			depth--
+			p.pos++
+			if depth == 0 {
+				command.End = p.pos
+				return command, nil
+			}
+
+		default:
+			arg, err := p.parseArgument()
+			if err != nil {
+				return Command{}, err
+			}
+
+			command.Args = append(command.Args, arg)
+		}
+	}
+
+	return Command{}, fmt.Errorf(
+		"line %v: unterminated command %v", command.Line, command.Name)
+}
+
+func (p *parser) parseArgument() (Argument, error) {
+	arg := Argument{Start: p.pos, Line: p.line}
+
+	switch {
+	case p.source[p.pos] == '"':
+		arg.Quoted = true
+		p.pos++
+
+		for p.pos < len(p.source) && p.source[p.pos] != '"' {
+			if p.source[p.pos] == '\\' {
+				p.pos++
+			}
+
+			if p.pos < len(p.source) && p.source[p.pos] == '\n' {
+				p.line++
+			}
+
+			p.pos++
+		}
+
+		if p.pos >= len(p.source) {
+			return Argument{}, fmt.Errorf(
+				"line %v: unterminated quoted argument", arg.Line)
+		}
+
+		arg.Value = p.source[arg.Start+1 : p.pos]
+		p.pos++
+
+	case p.bracketLength() >= 0:
+		arg.Quoted = true
+		value, err := p.readBracket()
+		if err != nil {
+			return Argument{}, err
+		}
+
+		arg.Value = value
+
+	default:
+		for p.pos < len(p.source) {
+			c := p.source[p.pos]
+			if c == ' ' || c == '\t' || c == '\r' || c == '\n' ||
+				c == '(' || c == ')' || c == '#' {
+				break
+			}
+
+			if c == '\\' {
+				p.pos++
+			} else if c == '"' {
+				// e.g., -DFOO="bar"
+				end := strings.IndexByte(p.source[p.pos+1:], '"')
+				if end >= 0 {
+					p.pos += end + 1
+				}
+			}
+
+			p.pos++
+		}
+
+		arg.Value = p.source[arg.Start:p.pos]
+	}
+
+	arg.End = p.pos
+	return arg, nil
+}
+
+func (p *parser) skipComment() error {
+	p.pos++ // '#'
+	if p.bracketLength() >= 0 {
+		_, err := p.readBracket()
+		return err
+	}
+
+	for p.pos < len(p.source) && p.source[p.pos] != '\n' {
+		p.pos++
+	}
+
+	return nil
+}
+
+// returns the number of '=' if a bracket starts at the current position,
+// e.g., [[ -> 0, [==[ -> 2, otherwise -1
+func (p *parser) bracketLength() int {
+	if p.pos >= len(p.source) || p.source[p.pos] != '[' {
+		return -1
+	}
+
+	length := 0
+	for i := p.pos + 1; i < len(p.source); i++ {
+		switch p.source[i] {
+		case '=':
+			length++
+		case '[':
+			return length
+		default:
+			return -1
+		}
+	}
+
+	return -1
+}
+
+func (p *parser) readBracket() (string, error) {
+	line := p.line
+	length := p.bracketLength()
+	p.pos += length + 2
+
+	closing := "]" + strings.Repeat("=", length) + "]"
+	end := strings.Index(p.source[p.pos:], closing)
+	if end < 0 {
+		return "", fmt.Errorf("line %v: unterminated bracket", line)
+	}
+
+	value := p.source[p.pos : p.pos+end]
+	p.line += strings.Count(value, "\n")
+	p.pos += end + len(closing)
+
+	return value, nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %v: %v", p.line, fmt.Sprintf(format, args...))
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
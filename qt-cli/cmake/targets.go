@@ -0,0 +1,309 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmake
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var targetCommandNames = []string{
+	"qt_add_executable", "qt6_add_executable", "add_executable",
+	"qt_add_library", "qt6_add_library", "add_library",
+}
+
+var qmlModuleCommandNames = []string{
+	"qt_add_qml_module", "qt6_add_qml_module",
+}
+
+var sourceFileExtensions = []string{
+	".h", ".hh", ".hpp", ".hxx", ".c", ".cc", ".cpp", ".cxx",
+	".ui", ".qrc",
+}
+
+var qmlFileExtensions = []string{
+	".qml", ".js", ".mjs",
+}
+
+// returns names of targets defined in this file
+func (l *ListFile) Targets() []string {
+	all := []string{}
+	for _, command := range l.FindCommands(targetCommandNames...) {
+		if !isRealTarget(command) {
+			continue
+		}
+
+		if name := command.FirstArg(); !slices.Contains(all, name) {
+			all = append(all, name)
+		}
+	}
+
+	return all
+}
+
+// if the target name is empty, the only target defined
+// in this file is picked
+func (l *ListFile) ResolveTarget(target string) (string, error) {
+	targets := l.Targets()
+
+	if len(target) != 0 {
+		if slices.Contains(targets, target) {
+			return target, nil
+		}
+
+		for _, command := range l.FindCommands("target_sources") {
+			if command.FirstArg() == target {
+				return target, nil
+			}
+		}
+
+		return "", fmt.Errorf(
+			"cannot find target '%v' in %v", target, l.Path)
+	}
+
+	switch len(targets) {
+	case 0:
+		return "", fmt.Errorf("cannot find any target in %v", l.Path)
+
+	case 1:
+		return targets[0], nil
+	}
+
+	return "", fmt.Errorf(
+		"multiple targets found in %v, specify one of %v",
+		l.Path, strings.Join(targets, ", "))
+}
+
+// adds the given files to the target and returns the ones actually added.
+// paths are relative to the directory of CMakeLists.txt.
+// C++ sources, forms and resources go to the command defining the target,
+// or target_sources() if the target is defined elsewhere. QML files go to
+// qt_add_qml_module(). other files are ignored.
+func (l *ListFile) AddSources(target string, files []string) ([]string, error) {
+	added := []string{}
+
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file))
+
+		var err error
+		var done bool
+
+		switch {
+		case slices.Contains(sourceFileExtensions, ext):
+			done, err = l.addSourceFile(target, file)
+
+		case slices.Contains(qmlFileExtensions, ext):
+			done, err = l.addQmlFile(target, file)
+		}
+
+		if err != nil {
+			return added, err
+		}
+
+		if done {
+			added = append(added, file)
+		}
+	}
+
+	return added, nil
+}
+
+// e.g., add_library(core STATIC), where sources go after STATIC
+var targetOptionKeywords = []string{
+	"STATIC", "SHARED", "MODULE", "OBJECT", "INTERFACE",
+	"WIN32", "MACOSX_BUNDLE", "EXCLUDE_FROM_ALL", "MANUAL_FINALIZATION",
+}
+
+func (l *ListFile) addSourceFile(target string, file string) (bool, error) {
+	command, found := l.findTargetCommand(target)
+	if found && containsArg(command, file) {
+		return false, nil
+	}
+
+	sourceCommands := []Command{}
+	for _, command := range l.FindCommands("target_sources") {
+		if command.FirstArg() != target {
+			continue
+		}
+
+		if containsArg(command, file) {
+			return false, nil
+		}
+
+		sourceCommands = append(sourceCommands, command)
+	}
+
+	// note, an existing target_sources(<target> PRIVATE ...) is preferred
+	for _, command := range sourceCommands {
+		section := findSection(command, "PRIVATE")
+		if len(section) != 0 {
+			return true, l.insertArgs(
+				command, section[len(section)-1], []string{file})
+		}
+	}
+
+	if found {
+		anchor := command.Args[0]
+		leading := true
+		for _, arg := range command.Args[1:] {
+			if leading && slices.Contains(targetOptionKeywords, arg.Value) {
+				anchor = arg
+				continue
+			}
+
+			leading = false
+			if !arg.IsKeyword() {
+				anchor = arg
+			}
+		}
+
+		return true, l.insertArgs(command, anchor, []string{file})
+	}
+
+	if len(sourceCommands) != 0 {
+		command := sourceCommands[0]
+		last := command.Args[len(command.Args)-1]
+		return true, l.insertArgs(command, last, []string{"PRIVATE", file})
+	}
+
+	return false, fmt.Errorf(
+		"cannot find where to add sources of '%v' in %v", target, l.Path)
+}
+
+func (l *ListFile) addQmlFile(target string, file string) (bool, error) {
+	for _, command := range l.FindCommands(qmlModuleCommandNames...) {
+		if command.FirstArg() != target {
+			continue
+		}
+
+		if containsArg(command, file) {
+			return false, nil
+		}
+
+		section := findSection(command, "QML_FILES")
+		if len(section) == 0 {
+			last := command.Args[len(command.Args)-1]
+			return true, l.insertArgs(
+				command, last, []string{"QML_FILES", file})
+		}
+
+		return true, l.insertArgs(
+			command, section[len(section)-1], []string{file})
+	}
+
+	return false, fmt.Errorf(
+		"cannot find qt_add_qml_module() of '%v' in %v", target, l.Path)
+}
+
+func (l *ListFile) findTargetCommand(target string) (Command, bool) {
+	for _, command := range l.FindCommands(targetCommandNames...) {
+		if command.FirstArg() == target && isRealTarget(command) {
+			return command, true
+		}
+	}
+
+	return Command{}, false
+}
+
+func (l *ListFile) insertArgs(
+	command Command,
+	after Argument,
+	values []string,
+) error {
+	offset, separator := l.findInsertion(command, after)
+
+	// a new keyword and its values are aligned with existing ones
+	multiLine := strings.HasPrefix(separator, "\n")
+	if isKeywordValue(values[0]) && multiLine {
+		separator = "\n" + l.findKeywordIndent(command)
+	}
+
+	text := ""
+	for index, value := range values {
+		if index != 0 && isKeywordValue(values[index-1]) && multiLine {
+			separator = "\n" + l.findValueIndent(command)
+		}
+
+		text += separator + value
+	}
+
+	return l.insert(offset, text)
+}
+
+func (l *ListFile) findKeywordIndent(command Command) string {
+	for _, arg := range command.Args[1:] {
+		if arg.IsKeyword() && l.startsLine(arg.Start) {
+			return l.lineIndent(arg.Start)
+		}
+	}
+
+	return l.findArgIndent(command)
+}
+
+func (l *ListFile) findValueIndent(command Command) string {
+	for _, arg := range command.Args[1:] {
+		if !arg.IsKeyword() && l.startsLine(arg.Start) {
+			return l.lineIndent(arg.Start)
+		}
+	}
+
+	return l.findKeywordIndent(command) + "    "
+}
+
+// returns values following the keyword until the next keyword
+func findSection(command Command, keyword string) []Argument {
+	section := []Argument{}
+	inSection := false
+
+	for _, arg := range command.Args[1:] {
+		if arg.IsKeyword() {
+			if inSection {
+				break
+			}
+
+			inSection = arg.Value == keyword
+			if inSection {
+				section = append(section, arg)
+			}
+			continue
+		}
+
+		if inSection {
+			section = append(section, arg)
+		}
+	}
+
+	return section
+}
+
+func containsArg(command Command, value string) bool {
+	for _, arg := range command.Args[1:] {
+		if arg.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isKeywordValue(value string) bool {
+	return Argument{Value: value}.IsKeyword()
+}
+
+// e.g., add_library(foo ALIAS bar) does not define a target to add to
+func isRealTarget(command Command) bool {
+	if len(command.Args) == 0 {
+		return false
+	}
+
+	for _, arg := range command.Args[1:] {
+		if arg.Value == "ALIAS" || arg.Value == "IMPORTED" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmake
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAddSources(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		files  []string
+		want   string
+		added  []string
+	}{
+		{
+			name:   "after the target name",
+			source: "add_executable(app)\n",
+			files:  []string{"Foo.h", "Foo.cpp"},
+			want:   "add_executable(app Foo.h Foo.cpp)\n",
+			added:  []string{"Foo.h", "Foo.cpp"},
+		},
+		{
+			name:   "after the last source",
+			source: "qt_add_executable(app main.cpp MANUAL_FINALIZATION)\n",
+			files:  []string{"Foo.cpp"},
+			want:   "qt_add_executable(app main.cpp Foo.cpp MANUAL_FINALIZATION)\n",
+			added:  []string{"Foo.cpp"},
+		},
+		{
+			name:   "after a library type",
+			source: "add_library(core STATIC)\n",
+			files:  []string{"Bar.h", "Bar.cpp"},
+			want:   "add_library(core STATIC Bar.h Bar.cpp)\n",
+			added:  []string{"Bar.h", "Bar.cpp"},
+		},
+		{
+			name:   "after leading options",
+			source: "qt_add_executable(app WIN32 MACOSX_BUNDLE MANUAL_FINALIZATION)\n",
+			files:  []string{"Foo.cpp"},
+			want: "qt_add_executable(app WIN32 MACOSX_BUNDLE " +
+				"MANUAL_FINALIZATION Foo.cpp)\n",
+			added: []string{"Foo.cpp"},
+		},
+		{
+			name: "to an existing target_sources",
+			source: "add_library(core STATIC)\n" +
+				"target_sources(core PRIVATE a.cpp)\n",
+			files: []string{"Bar.cpp"},
+			want: "add_library(core STATIC)\n" +
+				"target_sources(core PRIVATE a.cpp Bar.cpp)\n",
+			added: []string{"Bar.cpp"},
+		},
+		{
+			name: "to a multi-line target_sources",
+			source: "qt_add_executable(app main.cpp)\n" +
+				"target_sources(app\n    PRIVATE\n        a.cpp\n)\n",
+			files: []string{"Bar.cpp"},
+			want: "qt_add_executable(app main.cpp)\n" +
+				"target_sources(app\n    PRIVATE\n        a.cpp\n        Bar.cpp\n)\n",
+			added: []string{"Bar.cpp"},
+		},
+		{
+			name:   "not twice",
+			source: "add_executable(app main.cpp)\ntarget_sources(app PRIVATE a.cpp)\n",
+			files:  []string{"main.cpp", "a.cpp"},
+			want:   "add_executable(app main.cpp)\ntarget_sources(app PRIVATE a.cpp)\n",
+			added:  []string{},
+		},
+	}
+
+	for _, test := range tests {
+		l := &ListFile{Path: "CMakeLists.txt"}
+		if err := l.setSource(test.source); err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+
+		target := l.Targets()[0]
+		added, err := l.AddSources(target, test.files)
+		if err != nil {
+			t.Errorf("%v: unexpected error, %v", test.name, err)
+			continue
+		}
+
+		if l.Source != test.want {
+			t.Errorf("%v: got %q, want %q", test.name, l.Source, test.want)
+		}
+
+		if !slices.Equal(added, test.added) {
+			t.Errorf("%v: got added %v, want %v", test.name, added, test.added)
+		}
+	}
+}
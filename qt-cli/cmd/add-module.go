@@ -0,0 +1,89 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+	"qtcli/cmake"
+	"qtcli/util"
+	"regexp"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var moduleTarget string
+
+var componentNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+var addModuleCmd = &cobra.Command{
+	Use:   "module <name>... [--target <target>]",
+	Short: util.Msg("Add Qt modules to a CMake project"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			cmd.Help()
+			return
+		}
+
+		components := []string{}
+		for _, arg := range args {
+			component := cmake.QtComponentName(arg)
+			if !componentNameRegex.MatchString(component) {
+				logrus.Fatal(fmt.Errorf(
+					"invalid Qt module name, given = '%v'", arg))
+			}
+
+			if !slices.Contains(components, component) {
+				components = append(components, component)
+			}
+		}
+
+		if err := addQtModules(projectDir, moduleTarget, components); err != nil {
+			logrus.Fatal(err)
+		}
+	},
+}
+
+func addQtModules(dir string, target string, components []string) error {
+	path, err := cmake.FindListFile(dir)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debug(fmt.Sprintf("adding modules to %v", path))
+
+	listFile, err := cmake.ReadListFile(path)
+	if err != nil {
+		return err
+	}
+
+	target, err = listFile.ResolveTarget(target)
+	if err != nil {
+		return err
+	}
+
+	edit := &cmakeEdit{}
+	edit.touch(listFile)
+
+	if err := linkQtModules(edit, listFile, target, components); err != nil {
+		return err
+	}
+
+	if !edit.isModified() {
+		fmt.Printf("Nothing to add to target '%v' in %v\n", target, path)
+		return nil
+	}
+
+	return edit.apply(dryRun)
+}
+
+func init() {
+	flags := addModuleCmd.Flags()
+	flags.StringVarP(
+		&moduleTarget, "target", "t", "",
+		util.Msg("Target to link modules to, if there are several"))
+
+	addCmd.AddCommand(addModuleCmd)
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmd
+
+import (
+	"qtcli/util"
+
+	"github.com/spf13/cobra"
+)
+
+var projectDir string
+var dryRun bool
+
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: util.Msg("Add something to an existing project"),
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+func init() {
+	flags := addCmd.PersistentFlags()
+
+	flags.StringVarP(
+		&projectDir, "project-dir", "d", ".",
+		util.Msg("Directory to search for the project file upward from"))
+
+	flags.BoolVar(
+		&dryRun, "dry-run", false,
+		util.Msg("Show changes as a diff without writing them"))
+
+	rootCmd.AddCommand(addCmd)
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"qtcli/cmake"
+	"qtcli/generator"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// used when --add-to-cmake is given without a target name
+const cmakeTargetAuto = "auto"
+
+func addToCMake(
+	outputDir string,
+	target string,
+	result generator.GeneratorResult,
+) error {
+	if len(outputDir) == 0 {
+		return fmt.Errorf("--add-to-cmake requires --output-dir")
+	}
+
+	// paths are made relative to the project file
+	dir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return err
+	}
+
+	path, err := cmake.FindListFile(dir)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debug(fmt.Sprintf("adding files to %v", path))
+
+	listFile, err := cmake.ReadListFile(path)
+	if err != nil {
+		return err
+	}
+
+	if target == cmakeTargetAuto {
+		target = ""
+	}
+
+	target, err = listFile.ResolveTarget(target)
+	if err != nil {
+		return err
+	}
+
+	files := []string{}
+	for _, name := range result.FileNames {
+		rel, err := filepath.Rel(
+			listFile.Dir(), filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		files = append(files, filepath.ToSlash(rel))
+	}
+
+	edit := &cmakeEdit{}
+	edit.touch(listFile)
+
+	added, err := listFile.AddSources(target, files)
+	if err != nil {
+		return err
+	}
+
+	if len(added) != 0 {
+		edit.note("Added %v to target '%v' in %v",
+			strings.Join(added, ", "), target, path)
+	}
+
+	if err := linkQtModules(edit, listFile, target, result.QtModules); err != nil {
+		return err
+	}
+
+	return edit.apply(false)
+}
+
+// collects changes to list files so that they can be written at once,
+// or shown as a diff without writing
+type cmakeEdit struct {
+	files []*cmake.ListFile
+	notes []string
+}
+
+func (e *cmakeEdit) touch(listFile *cmake.ListFile) {
+	for _, file := range e.files {
+		if file.Path == listFile.Path {
+			return
+		}
+	}
+
+	e.files = append(e.files, listFile)
+}
+
+func (e *cmakeEdit) note(format string, args ...any) {
+	e.notes = append(e.notes, fmt.Sprintf(format, args...))
+}
+
+func (e *cmakeEdit) isModified() bool {
+	for _, file := range e.files {
+		if file.IsModified() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *cmakeEdit) apply(dryRun bool) error {
+	for _, file := range e.files {
+		if !file.IsModified() {
+			continue
+		}
+
+		if dryRun {
+			fmt.Print(file.Diff())
+			continue
+		}
+
+		logrus.Debug(fmt.Sprintf("writing %v", file.Path))
+		if err := file.Write(); err != nil {
+			return err
+		}
+	}
+
+	if !dryRun {
+		for _, note := range e.notes {
+			fmt.Println(note)
+		}
+	}
+
+	return nil
+}
+
+// adds components to find_package(Qt6 ...), which might be in one of
+// parent directories, and links them to the target
+func linkQtModules(
+	edit *cmakeEdit,
+	listFile *cmake.ListFile,
+	target string,
+	components []string,
+) error {
+	if len(components) == 0 {
+		return nil
+	}
+
+	packageFile := listFile
+	path, err := cmake.FindQtPackageListFile(listFile.Dir())
+	if err == nil && path != listFile.Path {
+		packageFile, err = cmake.ReadListFile(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	edit.touch(packageFile)
+
+	addedComponents, err := packageFile.AddQtComponents(components)
+	if err != nil {
+		return err
+	}
+
+	if len(addedComponents) != 0 {
+		edit.note("Added %v to find_package() in %v",
+			strings.Join(addedComponents, ", "), packageFile.Path)
+	}
+
+	linked, err := listFile.LinkQtModules(target, components)
+	if err != nil {
+		return err
+	}
+
+	if len(linked) != 0 {
+		edit.note("Linked %v to target '%v' in %v",
+			strings.Join(linked, ", "), target, listFile.Path)
+	}
+
+	return nil
+}
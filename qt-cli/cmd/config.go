@@ -0,0 +1,137 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"qtcli/config"
+	"qtcli/util"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var showTypeName string
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: util.Msg("Show settings read from configuration files"),
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show [<command>...] [--type <type>]",
+	Short: util.Msg("Show effective settings and where they came from"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			target, rest, err := rootCmd.Find(args)
+			if err != nil || len(rest) != 0 || target == rootCmd {
+				logrus.Fatal(fmt.Errorf(
+					"cannot find command, given = '%v'",
+					strings.Join(args, " ")))
+			}
+
+			if err := showSettings(target, true); err != nil {
+				logrus.Fatal(err)
+			}
+
+			return
+		}
+
+		files, err := readSettingFiles(cmd.Root())
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
+		if len(files) == 0 && len(config.EnvLayers()) == 0 {
+			fmt.Println("No settings found")
+			return
+		}
+
+		// settings other than defaults, for every command
+		for _, target := range findConfigurableCommands(rootCmd) {
+			if err := showSettings(target, false); err != nil {
+				logrus.Fatal(err)
+			}
+		}
+	},
+}
+
+func showSettings(target *cobra.Command, withDefaults bool) error {
+	settings, err := resolveSettings(target, showTypeName)
+	if err != nil {
+		return err
+	}
+
+	flags := pflag.NewFlagSet(target.Name(), pflag.ContinueOnError)
+	flags.AddFlagSet(target.LocalFlags())
+	flags.AddFlagSet(target.InheritedFlags())
+
+	lines := [][]string{}
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == "help" || flag.Name == "verbose" {
+			return
+		}
+
+		setting, found := settings[flag.Name]
+		if flag.Name == "type" && len(showTypeName) != 0 {
+			lines = append(lines, []string{flag.Name, showTypeName, "--type"})
+		} else if found {
+			value := strings.Join(settingValues(flag, setting), ",")
+			lines = append(lines, []string{flag.Name, value, setting.Origin})
+		} else if withDefaults {
+			lines = append(lines, []string{flag.Name, flag.DefValue, "default"})
+		}
+	})
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	fmt.Println(commandKey(target))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, line := range lines {
+		fmt.Fprintf(w, "  %v\t= %v\t(%v)\n", line[0], line[1], line[2])
+	}
+
+	return w.Flush()
+}
+
+// returns commands taking flags other than common ones
+func findConfigurableCommands(parent *cobra.Command) []*cobra.Command {
+	all := []*cobra.Command{}
+
+	for _, child := range parent.Commands() {
+		if slices.Contains([]string{"help", "completion", "config"},
+			child.Name()) {
+			continue
+		}
+
+		if child.HasSubCommands() {
+			all = append(all, findConfigurableCommands(child)...)
+			continue
+		}
+
+		all = append(all, child)
+	}
+
+	return all
+}
+
+func init() {
+	configShowCmd.Flags().StringVarP(
+		&showTypeName, "type", "t", "",
+		util.Msg("Show settings for the given type"))
+
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"qtcli/project"
+	"qtcli/util"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: util.Msg("Print targets and Qt modules of a project as JSON"),
+	Run: func(cmd *cobra.Command, args []string) {
+		info, err := project.Read(projectDir)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
+		logrus.Debug(fmt.Sprintf("read project %v", info.ProjectFile))
+
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
+		fmt.Println(string(data))
+	},
+}
+
+func init() {
+	flags := infoCmd.Flags()
+	flags.StringVarP(
+		&projectDir, "project-dir", "d", ".",
+		util.Msg("Directory to search for the project file upward from"))
+
+	rootCmd.AddCommand(infoCmd)
+}
@@ -0,0 +1,274 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"qtcli/generator"
+	"qtcli/util"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var fileGlobs []string
+var skipGlobs []string
+var checkOnly bool
+
+var licenseCmd = &cobra.Command{
+	Use:   "license",
+	Short: util.Msg("Manage license headers of existing files"),
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var licenseApplyCmd = &cobra.Command{
+	Use:   "apply [<path>...] [--license <id> | --license-file <path>]",
+	Short: util.Msg("Add license headers or update their years"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+
+		input := generator.LicenseInput{
+			License:      licenseName,
+			LicenseFile:  licenseTemplatePath,
+			Author:       authorName,
+			Email:        authorEmail,
+			Organization: organizationName,
+			Reuse:        reuseTags,
+			Env:          generator.NewEnvPolicy(allowedEnv, trustTemplates),
+		}
+
+		if err := validateLicenseInput(input); err != nil {
+			logrus.Fatal(err)
+		}
+
+		pending := 0
+		for _, root := range args {
+			count, err := applyLicense(input, root)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+
+			pending += count
+		}
+
+		reportEnvNames(input.Env.ReadNames())
+
+		if checkOnly && pending != 0 {
+			fmt.Printf("%v license change(s) needed\n", pending)
+			os.Exit(1)
+		}
+	},
+}
+
+func validateLicenseInput(input generator.LicenseInput) error {
+	if len(input.License) == 0 && len(input.LicenseFile) == 0 {
+		return fmt.Errorf(
+			"cannot apply license, given neither a license nor a license file")
+	}
+
+	return input.Validate()
+}
+
+// walks the path and adds or updates license headers of files with
+// a known comment style. returns the number of files changed, or needing
+// a change with --check.
+func applyLicense(input generator.LicenseInput, root string) (int, error) {
+	year := time.Now().Year()
+	count := 0
+
+	// git config of the tree being licensed
+	input.Dir = root
+	if stat, err := os.Stat(root); err == nil && !stat.IsDir() {
+		input.Dir = filepath.Dir(root)
+	}
+
+	err := filepath.WalkDir(root, func(
+		path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			rel = filepath.Base(path)
+		}
+
+		if entry.IsDir() {
+			if path != root && (strings.HasPrefix(entry.Name(), ".") ||
+				matchAnyGlob(skipGlobs, rel)) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() ||
+			matchAnyGlob(skipGlobs, rel) ||
+			(len(fileGlobs) != 0 && !matchAnyGlob(fileGlobs, rel)) {
+			return nil
+		}
+
+		style, found := generator.FindCommentStyle(path)
+		if !found {
+			logrus.Debug(fmt.Sprintf("skipping %v, unknown comment style", path))
+			return nil
+		}
+
+		changed, err := applyLicenseToFile(input, path, style, year)
+		if changed {
+			count++
+		}
+
+		return err
+	})
+	if err != nil || !input.Reuse {
+		return count, err
+	}
+
+	missing, err := applyLicenseTexts(input, input.Dir)
+	return count + missing, err
+}
+
+// creates license texts in LICENSES, or reports missing ones with --check
+func applyLicenseTexts(input generator.LicenseInput, dir string) (int, error) {
+	if checkOnly {
+		missing, err := generator.FindMissingLicenseTexts(input.License, dir)
+		for _, path := range missing {
+			fmt.Printf("Missing license text: %v\n", path)
+		}
+
+		return len(missing), err
+	}
+
+	created, err := generator.WriteLicenseTexts(input.License, dir)
+	for _, path := range created {
+		fmt.Printf("Created license text %v\n", path)
+	}
+
+	return len(created), err
+}
+
+func applyLicenseToFile(
+	input generator.LicenseInput,
+	path string,
+	style generator.CommentStyle,
+	year int,
+) (bool, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	fileName := filepath.Base(path)
+	className := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+
+	header, err := input.Render(className, fileName)
+	if err != nil {
+		return false, err
+	}
+
+	output, status := generator.ApplyLicenseHeader(
+		string(raw), header, style, year)
+
+	switch status {
+	case generator.LicenseHeaderUnchanged:
+		logrus.Debug(fmt.Sprintf("%v is up to date", path))
+		return false, nil
+
+	case generator.LicenseHeaderAdded:
+		if checkOnly {
+			fmt.Printf("Missing license header: %v\n", path)
+			return true, nil
+		}
+
+		fmt.Printf("Added license header to %v\n", path)
+
+	case generator.LicenseHeaderUpdated:
+		if checkOnly {
+			fmt.Printf("Outdated copyright year: %v\n", path)
+			return true, nil
+		}
+
+		fmt.Printf("Updated copyright year in %v\n", path)
+	}
+
+	return true, os.WriteFile(path, []byte(output), stat.Mode().Perm())
+}
+
+func matchAnyGlob(patterns []string, path string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		return util.MatchGlob(pattern, path)
+	})
+}
+
+func init() {
+	flags := licenseApplyCmd.Flags()
+
+	flags.StringVar(
+		&licenseName, "license", "",
+		util.Msg("Add a common license header, e.g., spdx:MIT"))
+
+	flags.StringVarP(
+		&licenseTemplatePath, "license-file", "l", "",
+		util.Msg("Specify a path to the license template file"))
+
+	flags.StringVar(
+		&authorName, "author", "",
+		util.Msg("Author name available to license templates"))
+
+	flags.StringVar(
+		&authorEmail, "email", "",
+		util.Msg("Author email available to license templates"))
+
+	flags.StringVar(
+		&organizationName, "organization", "",
+		util.Msg("Organization available to license templates"))
+
+	flags.BoolVar(
+		&reuseTags, "reuse", false,
+		util.Msg("Use REUSE tags and create LICENSES/<id>.txt"))
+
+	flags.StringSliceVar(
+		&allowedEnv, "allow-env", []string{},
+		util.Msg("Environment variables templates can read, e.g., MYAPP_*"))
+
+	flags.BoolVar(
+		&trustTemplates, "trust-templates", false,
+		util.Msg("Let templates read any environment variable"))
+
+	flags.StringSliceVar(
+		&fileGlobs, "files", []string{},
+		util.Msg("Only process files matching the pattern, e.g., src/**/*.cpp"))
+
+	flags.StringSliceVar(
+		&skipGlobs, "skip", []string{},
+		util.Msg("Skip files and directories matching the pattern"))
+
+	flags.BoolVar(
+		&checkOnly, "check", false,
+		util.Msg("Report files to change without writing, and fail if any"))
+
+	licenseCmd.AddCommand(licenseApplyCmd)
+	rootCmd.AddCommand(licenseCmd)
+}
@@ -4,8 +4,11 @@
 package cmd
 
 import (
+	"fmt"
 	"qtcli/generator"
 	"qtcli/util"
+	"slices"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -17,6 +20,20 @@ var base string
 var cppMacroList []string
 var cppIncludeList []string
 var cppIsQObject bool
+var cppFileNaming string
+var cppHeaderExt string
+var cppSourceExt string
+
+var modelRoleList []string
+
+var formEmbedding string
+var formUseUiLoader bool
+var formUiFile string
+var formConnectList []string
+var formEmbeddingNames = []string{"pointer", "aggregation", "inheritance"}
+
+var cmakeTarget string
+var addToQMakeProject bool
 
 var pythonModuleName string
 var pythonImportList []string
@@ -25,18 +42,79 @@ var newClassCmd = &cobra.Command{
 	Use:   "class <name> --type <type>",
 	Short: util.Msg("Create a new class"),
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) < 1 {
+		if len(args) < 1 && len(formUiFile) == 0 {
 			cmd.Help()
 			return
 		}
 
+		name := ""
+		if len(args) != 0 {
+			name = args[0]
+		}
+
+		if len(formConnectList) != 0 && len(formUiFile) == 0 {
+			logrus.Fatal("--connect can be used only with --from-ui")
+		}
+
+		form := generator.UiForm{}
+		slots := []generator.FormSlot{}
+		if len(formUiFile) != 0 {
+			var err error
+			form, err = generator.ReadUiFile(formUiFile)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+
+			slots, err = form.CreateSlots(formConnectList)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+
+			if len(name) == 0 {
+				name = form.Class
+			}
+
+			if len(classType) == 0 {
+				classType = "cpp-form"
+			}
+
+			if len(base) == 0 {
+				base = form.Widget.Class
+			}
+		}
+
+		if len(classType) == 0 {
+			logrus.Fatal(`required flag(s) "type" not set`)
+		}
+
+		if !slices.Contains(formEmbeddingNames, formEmbedding) {
+			logrus.Fatal(fmt.Errorf(
+				"invalid ui embedding, given = '%v', expected one of %v",
+				formEmbedding, formEmbeddingNames))
+		}
+
+		if err := validateCppFileNaming(); err != nil {
+			logrus.Fatal(err)
+		}
+
 		g := generator.NewGenerator(&generator.GeneratorInputData{
 			Category:          generator.TargetCategoryClass,
 			Type:              classType,
-			Name:              args[0],
+			Name:              name,
 			OutputDir:         outputDir,
 			LicenseFile:       licenseTemplatePath,
+			License:           licenseName,
 			CustomTemplateDir: customTemplateDir,
+			Author:            authorName,
+			Email:             authorEmail,
+			Organization:      organizationName,
+			Reuse:             reuseTags,
+			AllowedEnv:        allowedEnv,
+			TrustTemplates:    trustTemplates,
+
+			AllowSiblingOutput: allowSiblingOutput,
+			Strict:             strictKeys,
+			WarnMissingKeys:    warnMissingKeys,
 
 			CppBaseClass:      base,
 			CppMacroList:      cppMacroList,
@@ -44,14 +122,43 @@ var newClassCmd = &cobra.Command{
 			CppClassIsQObject: cppIsQObject,
 			CppUsePragma:      true,
 
+			CppFileNaming:      cppFileNaming,
+			CppHeaderExtension: cppHeaderExt,
+			CppSourceExtension: cppSourceExt,
+
+			ModelRoleList: modelRoleList,
+
+			FormEmbedding:   formEmbedding,
+			FormUseUiLoader: formUseUiLoader,
+			FormUiFile:      formUiFile,
+			FormUiClass:     form.Class,
+			FormSlots:       slots,
+
 			PythonBaseClass:  base,
 			PythonModuleName: pythonModuleName,
 			PythonImportList: pythonImportList,
 		})
 
-		_, err := g.Run()
+		result, err := g.Run()
 		if err != nil {
-			logrus.Fatal(err)
+			exitWithGeneratorError(err)
+		}
+
+		reportEnvNames(result.EnvNames)
+		reportMissingKeys(result.MissingKeys)
+
+		if cmd.Flags().Changed("add-to-cmake") {
+			err := addToCMake(outputDir, cmakeTarget, result)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+		}
+
+		if addToQMakeProject {
+			err := addToQMake(outputDir, result.FileNames)
+			if err != nil {
+				logrus.Fatal(err)
+			}
 		}
 	},
 }
@@ -67,7 +174,14 @@ func init() {
 		&base, "base", "b", "",
 		util.Msg("Base class name"))
 
-	newClassCmd.MarkFlagRequired("type")
+	flags.StringVar(
+		&cmakeTarget, "add-to-cmake", "",
+		util.Msg("Add created files to a target in CMakeLists.txt"))
+	flags.Lookup("add-to-cmake").NoOptDefVal = cmakeTargetAuto
+
+	flags.BoolVar(
+		&addToQMakeProject, "add-to-qmake", false,
+		util.Msg("Add created files to the nearest .pro or .pri file"))
 
 	// cpp related
 	flags.StringSliceVarP(
@@ -82,6 +196,41 @@ func init() {
 		&cppIsQObject, "qobject", "q", false,
 		util.Msg("Specify if class is a QObject-derived class"))
 
+	flags.StringVar(
+		&cppFileNaming, "file-naming", generator.FileNamingAsIs,
+		util.Msg("How to name files after the class (as-is, lowercase, snake_case)"))
+
+	flags.StringVar(
+		&cppHeaderExt, "header-ext", ".h",
+		util.Msg("Extension of a header file (.h, .hpp, .hh, .hxx)"))
+
+	flags.StringVar(
+		&cppSourceExt, "source-ext", ".cpp",
+		util.Msg("Extension of a source file (.cpp, .cc, .cxx)"))
+
+	// model related
+	flags.StringSliceVar(
+		&modelRoleList, "role", []string{},
+		util.Msg("Role name to add to an item model class"))
+
+	// form related
+	flags.StringVar(
+		&formEmbedding, "ui-embedding", "pointer",
+		util.Msg("How to embed Ui:: class (pointer, aggregation, inheritance)"))
+
+	flags.BoolVar(
+		&formUseUiLoader, "ui-loader", false,
+		util.Msg("Load .ui file at runtime using QUiLoader (Python only)"))
+
+	flags.StringVar(
+		&formUiFile, "from-ui", "",
+		util.Msg("Create a form class for an existing .ui file"))
+
+	flags.StringSliceVar(
+		&formConnectList, "connect", []string{},
+		util.Msg("Object in .ui file to add an auto-connected slot for "+
+			"(e.g., okButton, checkBox:toggled)"))
+
 	// python related
 	flags.StringVarP(
 		&pythonModuleName, "module", "m", "PySide6",
@@ -93,3 +242,28 @@ func init() {
 
 	newCmd.AddCommand(newClassCmd)
 }
+
+// note, an extension can be given without the leading dot
+func validateCppFileNaming() error {
+	if !slices.Contains(generator.FileNamingNames, cppFileNaming) {
+		return fmt.Errorf(
+			"invalid file naming, given = '%v', expected one of %v",
+			cppFileNaming, generator.FileNamingNames)
+	}
+
+	cppHeaderExt = "." + strings.TrimPrefix(cppHeaderExt, ".")
+	if !slices.Contains(generator.CppHeaderExtensions, cppHeaderExt) {
+		return fmt.Errorf(
+			"invalid header extension, given = '%v', expected one of %v",
+			cppHeaderExt, generator.CppHeaderExtensions)
+	}
+
+	cppSourceExt = "." + strings.TrimPrefix(cppSourceExt, ".")
+	if !slices.Contains(generator.CppSourceExtensions, cppSourceExt) {
+		return fmt.Errorf(
+			"invalid source extension, given = '%v', expected one of %v",
+			cppSourceExt, generator.CppSourceExtensions)
+	}
+
+	return nil
+}
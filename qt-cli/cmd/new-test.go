@@ -0,0 +1,135 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+	"qtcli/generator"
+	"qtcli/util"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var testType string
+var testCaseList []string
+var testUsePytest bool
+var testHeaderFile string
+
+var newTestCmd = &cobra.Command{
+	Use:   "test [<name>] [--for <header>]",
+	Short: util.Msg("Create a new test class"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 && len(testHeaderFile) == 0 {
+			cmd.Help()
+			return
+		}
+
+		name := ""
+		if len(args) != 0 {
+			name = args[0]
+		}
+
+		caseList := testCaseList
+		if len(testHeaderFile) != 0 {
+			decl, err := findTestedClass(testHeaderFile, name)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+
+			if len(name) == 0 {
+				name = decl.Name
+			}
+
+			caseList = append(caseList, decl.TestFunctionNames()...)
+		}
+
+		g := generator.NewGenerator(&generator.GeneratorInputData{
+			Category:          generator.TargetCategoryTest,
+			Type:              testType,
+			Name:              name,
+			OutputDir:         outputDir,
+			LicenseFile:       licenseTemplatePath,
+			License:           licenseName,
+			CustomTemplateDir: customTemplateDir,
+			Author:            authorName,
+			Email:             authorEmail,
+			Organization:      organizationName,
+			Reuse:             reuseTags,
+			AllowedEnv:        allowedEnv,
+			TrustTemplates:    trustTemplates,
+
+			AllowSiblingOutput: allowSiblingOutput,
+			Strict:             strictKeys,
+			WarnMissingKeys:    warnMissingKeys,
+
+			PythonModuleName: pythonModuleName,
+
+			TestCaseList:   caseList,
+			TestUsePytest:  testUsePytest,
+			TestHeaderFile: testHeaderFile,
+		})
+
+		result, err := g.Run()
+		if err != nil {
+			exitWithGeneratorError(err)
+		}
+
+		reportEnvNames(result.EnvNames)
+		reportMissingKeys(result.MissingKeys)
+	},
+}
+
+func init() {
+	// common
+	flags := newTestCmd.Flags()
+	flags.StringVarP(
+		&testType, "type", "t", "cpp",
+		util.Msg("Specify test type to create (cpp, python)"))
+
+	flags.StringSliceVarP(
+		&testCaseList, "case", "c", []string{},
+		util.Msg("Name of a data-driven test function to add"))
+
+	flags.StringVar(
+		&testHeaderFile, "for", "",
+		util.Msg("Header file of the class to create test functions for"))
+
+	// python related
+	flags.StringVarP(
+		&pythonModuleName, "module", "m", "PySide6",
+		util.Msg("Qt for Python Module"))
+
+	flags.BoolVar(
+		&testUsePytest, "pytest", false,
+		util.Msg("Use pytest-qt instead of unittest"))
+
+	newCmd.AddCommand(newTestCmd)
+}
+
+// if the name is given, the class with the same name is picked.
+// otherwise, the first class defined in the header is used.
+func findTestedClass(
+	headerFile string,
+	name string,
+) (generator.CppClassDecl, error) {
+	all, err := generator.ReadCppHeaderFile(headerFile)
+	if err != nil {
+		return generator.CppClassDecl{}, err
+	}
+
+	for _, decl := range all {
+		if len(name) == 0 || decl.Name == name {
+			return decl, nil
+		}
+	}
+
+	if len(name) != 0 {
+		return generator.CppClassDecl{}, fmt.Errorf(
+			"cannot find class '%v' in %v", name, headerFile)
+	}
+
+	return generator.CppClassDecl{}, fmt.Errorf(
+		"cannot find any class in %v", headerFile)
+}
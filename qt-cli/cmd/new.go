@@ -4,15 +4,30 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"qtcli/prompt"
 	"qtcli/util"
+	"strings"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var outputDir string
 var customTemplateDir string
 var licenseTemplatePath string
+var licenseName string
+var authorName string
+var authorEmail string
+var organizationName string
+var reuseTags bool
+var allowedEnv []string
+var trustTemplates bool
+var allowSiblingOutput bool
+var strictKeys bool
+var warnMissingKeys bool
 
 var newCmd = &cobra.Command{
 	Use:   "new",
@@ -22,6 +37,41 @@ var newCmd = &cobra.Command{
 	},
 }
 
+// tells which environment variables templates read, on stderr
+// not to mix with generated contents
+func reportEnvNames(names []string) {
+	if len(names) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Templates read environment variables: %v\n",
+		strings.Join(names, ", "))
+}
+
+// lists keys templates refer to but not defined, on stderr
+func reportMissingKeys(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "Templates refer to missing keys:")
+	for _, key := range keys {
+		fmt.Fprintf(os.Stderr, "  %v\n", key)
+	}
+}
+
+// prints an error in a template as it is, so that the excerpt of
+// the source is readable, and exits
+func exitWithGeneratorError(err error) {
+	var sourceErr util.SourceError
+	if !errors.As(err, &sourceErr) {
+		logrus.Fatal(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	os.Exit(1)
+}
+
 func init() {
 	flags := newCmd.PersistentFlags()
 
@@ -37,5 +87,45 @@ func init() {
 		&licenseTemplatePath, "license-file", "l", "",
 		util.Msg("Specify a path to the license template file"))
 
+	flags.StringVar(
+		&licenseName, "license", "",
+		util.Msg("Add a common license header, e.g., spdx:MIT"))
+
+	flags.StringVar(
+		&authorName, "author", "",
+		util.Msg("Author name available to license templates"))
+
+	flags.StringVar(
+		&authorEmail, "email", "",
+		util.Msg("Author email available to license templates"))
+
+	flags.StringVar(
+		&organizationName, "organization", "",
+		util.Msg("Organization available to license templates"))
+
+	flags.BoolVar(
+		&reuseTags, "reuse", false,
+		util.Msg("Use REUSE tags and create LICENSES/<id>.txt"))
+
+	flags.StringSliceVar(
+		&allowedEnv, "allow-env", []string{},
+		util.Msg("Environment variables templates can read, e.g., MYAPP_*"))
+
+	flags.BoolVar(
+		&trustTemplates, "trust-templates", false,
+		util.Msg("Let templates read any environment variable"))
+
+	flags.BoolVar(
+		&allowSiblingOutput, "allow-sibling-output", false,
+		util.Msg("Let templates write next to the output directory"))
+
+	flags.BoolVar(
+		&strictKeys, "strict", false,
+		util.Msg("Fail if templates refer to a missing key"))
+
+	flags.BoolVar(
+		&warnMissingKeys, "warn-missing-keys", false,
+		util.Msg("List keys templates refer to but missing after a run"))
+
 	rootCmd.AddCommand(newCmd)
 }
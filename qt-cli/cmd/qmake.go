@@ -0,0 +1,65 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"qtcli/qmake"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+func addToQMake(outputDir string, fileNames []string) error {
+	if len(outputDir) == 0 {
+		return fmt.Errorf("--add-to-qmake requires --output-dir")
+	}
+
+	// paths are made relative to the project file
+	dir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return err
+	}
+
+	path, err := qmake.FindProjectFile(dir)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debug(fmt.Sprintf("adding files to %v", path))
+
+	project, err := qmake.ReadProjectFile(path)
+	if err != nil {
+		return err
+	}
+
+	files := []string{}
+	for _, name := range fileNames {
+		rel, err := filepath.Rel(
+			project.Dir(), filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		files = append(files, filepath.ToSlash(rel))
+	}
+
+	added, err := project.AddFiles(files)
+	if err != nil {
+		return err
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	if err := project.Write(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added %v to %v\n", strings.Join(added, ", "), path)
+
+	return nil
+}
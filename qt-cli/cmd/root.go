@@ -20,6 +20,10 @@ var rootCmd = &cobra.Command{
 		if verbose {
 			logrus.SetLevel(logrus.DebugLevel)
 		}
+
+		if err := applySettings(cmd); err != nil {
+			logrus.Fatal(err)
+		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
@@ -0,0 +1,287 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"qtcli/config"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flags taking a path, which is relative to the file setting it
+var pathFlagNames = []string{
+	"output-dir", "template-dir", "license-file", "project-dir",
+	"from-ui", "for",
+}
+
+// flags loosening protections against third-party templates, which
+// a project file checked in a cloned repository must not set
+var userOnlyFlagNames = []string{
+	"allow-env", "trust-templates", "allow-sibling-output",
+}
+
+// sets flags not given on the command line from configuration files
+func applySettings(cmd *cobra.Command) error {
+	// e.g., config show --type is not the type of a template
+	if cmd.HasParent() && cmd.Parent() == configCmd {
+		return nil
+	}
+
+	flags := cmd.Flags()
+
+	typeName := ""
+	if flags.Changed("type") {
+		typeName = flags.Lookup("type").Value.String()
+	}
+
+	settings, err := resolveSettings(cmd, typeName)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range config.Names(settings) {
+		setting := settings[name]
+
+		flag := flags.Lookup(name)
+		if flag == nil {
+			logrus.Debug(fmt.Sprintf(
+				"ignoring setting '%v' unknown to '%v'", name, cmd.Name()))
+			continue
+		}
+
+		if flags.Changed(name) {
+			continue
+		}
+
+		for _, value := range settingValues(flag, setting) {
+			if err := flags.Set(name, value); err != nil {
+				return fmt.Errorf("%v: cannot set '%v', given = '%v'",
+					setting.Origin, name, value)
+			}
+		}
+
+		logrus.Debug(fmt.Sprintf("set '%v' to '%v' from %v",
+			name, flag.Value.String(), setting.Origin))
+	}
+
+	return nil
+}
+
+// returns settings applying to the command. if the type is not given,
+// the one in settings or the default value of --type is used.
+//
+// note,
+// from the lowest precedence, settings come from the user file, the project
+// file and QTCLI_* variables. in each file, 'types' takes precedence over
+// 'commands', and 'commands' over 'defaults'. flags given on the command
+// line take precedence over all of them.
+func resolveSettings(
+	cmd *cobra.Command,
+	typeName string,
+) (map[string]config.Setting, error) {
+	files, err := readSettingFiles(cmd.Root())
+	if err != nil {
+		return map[string]config.Setting{}, err
+	}
+
+	command := commandKey(cmd)
+
+	if len(typeName) == 0 {
+		layers, err := collectLayers(files, command, "")
+		if err != nil {
+			return map[string]config.Setting{}, err
+		}
+
+		if setting, found := config.Resolve(layers)["type"]; found {
+			typeName = setting.Values[len(setting.Values)-1]
+		} else if flag := cmd.Flags().Lookup("type"); flag != nil {
+			typeName = flag.DefValue
+		}
+	}
+
+	layers, err := collectLayers(files, command, typeName)
+	if err != nil {
+		return map[string]config.Setting{}, err
+	}
+
+	return config.Resolve(layers), nil
+}
+
+func collectLayers(
+	files []*config.File,
+	command string,
+	typeName string,
+) ([]config.Layer, error) {
+	all := []config.Layer{}
+
+	for _, file := range files {
+		layers, err := file.Layers(command, typeName)
+		if err != nil {
+			return all, err
+		}
+
+		all = append(all, layers...)
+	}
+
+	return append(all, config.EnvLayers()...), nil
+}
+
+// returns the user file and the project file, if any
+func readSettingFiles(root *cobra.Command) ([]*config.File, error) {
+	all := []*config.File{}
+
+	userPath, err := config.UserFilePath()
+	if err != nil {
+		return all, err
+	}
+
+	projectPath, err := config.FindProjectFile(".")
+	if err != nil {
+		return all, err
+	}
+
+	for _, path := range []string{userPath, projectPath} {
+		if len(path) == 0 {
+			continue
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		logrus.Debug(fmt.Sprintf("reading settings from %v", path))
+
+		file, err := config.ReadFile(path)
+		if err != nil {
+			return all, err
+		}
+
+		if path == projectPath {
+			if err := checkProjectFile(file); err != nil {
+				return all, err
+			}
+		}
+
+		if err := checkDefaults(file, root); err != nil {
+			return all, err
+		}
+
+		all = append(all, file)
+	}
+
+	return all, nil
+}
+
+func checkProjectFile(file *config.File) error {
+	for _, name := range file.Names() {
+		if slices.Contains(userOnlyFlagNames, name) {
+			return fmt.Errorf(
+				"%v: cannot set '%v' in a project file, give it as a flag, "+
+					"in the user config file or by QTCLI_* variables",
+				file.Path, name)
+		}
+	}
+
+	return nil
+}
+
+// note,
+// a name in 'defaults' applies to every command with the flag, so a flag
+// meaning different things to commands, e.g., --type of new class and
+// new test, must be set in 'commands' instead
+func checkDefaults(file *config.File, root *cobra.Command) error {
+	ambiguous := ambiguousFlagNames(root)
+
+	names := []string{}
+	for name := range file.Defaults {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		if slices.Contains(ambiguous, name) {
+			return fmt.Errorf(
+				"%v: cannot set '%v' in defaults, commands give it different "+
+					"meanings, set it in 'commands' instead",
+				file.Path, name)
+		}
+	}
+
+	return nil
+}
+
+// returns names of flags defined by several commands with different usages
+func ambiguousFlagNames(root *cobra.Command) []string {
+	usages := map[string]string{}
+	all := []string{}
+
+	var visit func(cmd *cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		// note, settings do not apply to config subcommands
+		if cmd == configCmd {
+			return
+		}
+
+		cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+			usage, found := usages[flag.Name]
+			if !found {
+				usages[flag.Name] = flag.Usage
+				return
+			}
+
+			if usage != flag.Usage && !slices.Contains(all, flag.Name) {
+				all = append(all, flag.Name)
+			}
+		})
+
+		for _, child := range cmd.Commands() {
+			visit(child)
+		}
+	}
+
+	visit(root)
+	slices.Sort(all)
+	return all
+}
+
+// returns values to pass to the flag. a relative path is resolved against
+// the directory of the file setting it, and 'true' turns into the value
+// used when the flag is given without a value, e.g., --add-to-cmake.
+func settingValues(flag *pflag.Flag, setting config.Setting) []string {
+	all := []string{}
+
+	for _, value := range setting.Values {
+		if flag.Value.Type() != "bool" && len(flag.NoOptDefVal) != 0 {
+			switch value {
+			case "true":
+				value = flag.NoOptDefVal
+			case "false":
+				continue
+			}
+		}
+
+		if slices.Contains(pathFlagNames, flag.Name) &&
+			len(value) != 0 && !filepath.IsAbs(value) {
+			value = filepath.Join(setting.Dir, value)
+		}
+
+		all = append(all, value)
+	}
+
+	return all
+}
+
+// e.g., "qtcli new class" -> "new class"
+func commandKey(cmd *cobra.Command) string {
+	path := strings.Fields(cmd.CommandPath())
+	return strings.Join(path[1:], " ")
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// note,
+// keys are flag names, e.g., license-file, and values are either
+// a scalar or a list of scalars
+type Values map[string]any
+
+type File struct {
+	Path     string            `yaml:"-"`
+	Defaults Values            `yaml:"defaults"`
+	Commands map[string]Values `yaml:"commands"`
+	Types    map[string]Values `yaml:"types"`
+}
+
+// a set of values from a single source. relative paths in the values
+// are relative to Dir.
+type Layer struct {
+	Origin string
+	Dir    string
+	Values map[string][]string
+}
+
+type Setting struct {
+	Name   string
+	Values []string
+	Origin string
+	Dir    string
+}
+
+func ReadFile(path string) (*File, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{}
+	if err := yaml.Unmarshal(raw, file); err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+
+	file.Path = path
+	return file, nil
+}
+
+// returns layers applying to the command, e.g., "new class", and
+// the type, e.g., "cpp", from the least specific to the most specific
+func (f *File) Layers(command string, typeName string) ([]Layer, error) {
+	all := []Layer{}
+
+	add := func(section string, values Values) error {
+		if len(values) == 0 {
+			return nil
+		}
+
+		layer, err := newLayer(
+			fmt.Sprintf("%v (%v)", f.Path, section),
+			filepath.Dir(f.Path), values)
+		if err != nil {
+			return fmt.Errorf("%v: %v", f.Path, err)
+		}
+
+		all = append(all, layer)
+		return nil
+	}
+
+	if err := add("defaults", f.Defaults); err != nil {
+		return all, err
+	}
+
+	if err := add("commands."+command, f.Commands[command]); err != nil {
+		return all, err
+	}
+
+	if len(typeName) != 0 {
+		if err := add("types."+typeName, f.Types[typeName]); err != nil {
+			return all, err
+		}
+	}
+
+	return all, nil
+}
+
+// returns names set in any section of the file, in alphabetical order
+func (f *File) Names() []string {
+	all := []string{}
+
+	sections := []Values{f.Defaults}
+	for _, values := range f.Commands {
+		sections = append(sections, values)
+	}
+
+	for _, values := range f.Types {
+		sections = append(sections, values)
+	}
+
+	for _, values := range sections {
+		for name := range values {
+			if !slices.Contains(all, name) {
+				all = append(all, name)
+			}
+		}
+	}
+
+	slices.Sort(all)
+	return all
+}
+
+// returns the value of the name in the most specific layer
+func Resolve(layers []Layer) map[string]Setting {
+	all := map[string]Setting{}
+
+	for _, layer := range layers {
+		for name, values := range layer.Values {
+			all[name] = Setting{
+				Name:   name,
+				Values: values,
+				Origin: layer.Origin,
+				Dir:    layer.Dir,
+			}
+		}
+	}
+
+	return all
+}
+
+// returns names of settings in alphabetical order
+func Names(settings map[string]Setting) []string {
+	all := []string{}
+	for name := range settings {
+		all = append(all, name)
+	}
+
+	slices.Sort(all)
+	return all
+}
+
+func newLayer(origin string, dir string, values Values) (Layer, error) {
+	layer := Layer{
+		Origin: origin,
+		Dir:    dir,
+		Values: map[string][]string{},
+	}
+
+	for name, value := range values {
+		switch v := value.(type) {
+		case []any:
+			items := []string{}
+			for _, item := range v {
+				if !isScalar(item) {
+					return layer, fmt.Errorf(
+						"cannot use nested value, given = '%v'", name)
+				}
+
+				items = append(items, fmt.Sprint(item))
+			}
+
+			layer.Values[name] = items
+
+		default:
+			if !isScalar(v) {
+				return layer, fmt.Errorf(
+					"cannot use nested value, given = '%v'", name)
+			}
+
+			layer.Values[name] = []string{fmt.Sprint(v)}
+		}
+	}
+
+	return layer, nil
+}
+
+func isScalar(value any) bool {
+	switch value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const ProjectFileName = ".qtcli.yml"
+
+// walks up from the given directory until a .qtcli.yml is found,
+// and returns an empty string if there is none
+func FindProjectFile(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if stat, err := os.Stat(path); err == nil && stat.Mode().IsRegular() {
+			return path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
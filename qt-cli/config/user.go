@@ -0,0 +1,73 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+const EnvPrefix = "QTCLI_"
+
+// returns $XDG_CONFIG_HOME/qtcli/config.yml, which defaults to
+// ~/.config/qtcli/config.yml. on Windows, %AppData% is used instead.
+func UserFilePath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+
+	if len(dir) == 0 && runtime.GOOS == "windows" {
+		var err error
+		dir, err = os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(dir) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "qtcli", "config.yml"), nil
+}
+
+// returns a layer for each QTCLI_<FLAG> variable, e.g.,
+// QTCLI_LICENSE_FILE -> license-file. relative paths are
+// relative to the current directory.
+func EnvLayers() []Layer {
+	all := []Layer{}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		dir = "."
+	}
+
+	env := os.Environ()
+	slices.Sort(env)
+
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, EnvPrefix) || key == EnvPrefix {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimPrefix(key, EnvPrefix))
+		name = strings.ReplaceAll(name, "_", "-")
+
+		all = append(all, Layer{
+			Origin: fmt.Sprintf("environment %v", key),
+			Dir:    dir,
+			Values: map[string][]string{name: {value}},
+		})
+	}
+
+	return all
+}
@@ -0,0 +1,149 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+type CommentStyle struct {
+	Line       string
+	BlockStart string
+	BlockEnd   string
+}
+
+var (
+	commentStyleSlash = CommentStyle{Line: "//", BlockStart: "/*", BlockEnd: "*/"}
+	commentStyleHash  = CommentStyle{Line: "#"}
+	commentStyleXml   = CommentStyle{BlockStart: "<!--", BlockEnd: "-->"}
+)
+
+var commentStyleByExtension = map[string]CommentStyle{
+	".h": commentStyleSlash, ".hh": commentStyleSlash,
+	".hpp": commentStyleSlash, ".hxx": commentStyleSlash,
+	".c": commentStyleSlash, ".cc": commentStyleSlash,
+	".cpp": commentStyleSlash, ".cxx": commentStyleSlash,
+	".qml": commentStyleSlash, ".js": commentStyleSlash,
+	".mjs": commentStyleSlash,
+
+	".py": commentStyleHash, ".cmake": commentStyleHash,
+	".pro": commentStyleHash, ".pri": commentStyleHash,
+	".prf": commentStyleHash, ".sh": commentStyleHash,
+	".yml": commentStyleHash, ".yaml": commentStyleHash,
+
+	".ui": commentStyleXml, ".qrc": commentStyleXml,
+	".xml": commentStyleXml,
+}
+
+// returns the comment syntax of the file, and false if unknown
+func FindCommentStyle(fileName string) (CommentStyle, bool) {
+	if filepath.Base(fileName) == "CMakeLists.txt" {
+		return commentStyleHash, true
+	}
+
+	style, found := commentStyleByExtension[strings.ToLower(
+		filepath.Ext(fileName))]
+	return style, found
+}
+
+// turns the text into a comment. a text already commented in this style
+// is kept as is, and one commented in another style is converted.
+func (s CommentStyle) Comment(text string) string {
+	text = strings.TrimRight(text, " \t\r\n")
+	if len(strings.TrimSpace(text)) == 0 {
+		return ""
+	}
+
+	if s.isCommented(text) {
+		return text + "\n"
+	}
+
+	lines := strings.Split(uncomment(text), "\n")
+
+	var b strings.Builder
+	if len(s.Line) != 0 {
+		for _, line := range lines {
+			if len(strings.TrimSpace(line)) == 0 {
+				b.WriteString(s.Line + "\n")
+			} else {
+				b.WriteString(s.Line + " " + line + "\n")
+			}
+		}
+
+		return b.String()
+	}
+
+	b.WriteString(s.BlockStart + "\n")
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight("  "+line, " ") + "\n")
+	}
+	b.WriteString(s.BlockEnd + "\n")
+
+	return b.String()
+}
+
+func (s CommentStyle) isCommented(text string) bool {
+	if len(s.Line) != 0 && isLineCommented(text, s.Line) {
+		return true
+	}
+
+	return len(s.BlockStart) != 0 && isBlockCommented(text, s)
+}
+
+// removes comment markers of any known style
+func uncomment(text string) string {
+	for _, style := range []CommentStyle{
+		commentStyleSlash, commentStyleHash, commentStyleXml,
+	} {
+		if len(style.Line) != 0 && isLineCommented(text, style.Line) {
+			lines := strings.Split(text, "\n")
+			for i, line := range lines {
+				line = strings.TrimPrefix(strings.TrimSpace(line), style.Line)
+				lines[i] = strings.TrimPrefix(line, " ")
+			}
+
+			return strings.Join(lines, "\n")
+		}
+
+		if len(style.BlockStart) != 0 && isBlockCommented(text, style) {
+			text = strings.TrimSpace(text)
+			text = strings.TrimPrefix(text, style.BlockStart)
+			text = strings.TrimSuffix(text, style.BlockEnd)
+
+			// e.g., ' * Copyright' in /* */ comments
+			lines := strings.Split(strings.TrimSpace(text), "\n")
+			for i, line := range lines {
+				trimmed := strings.TrimSpace(line)
+				if style == commentStyleSlash && strings.HasPrefix(trimmed, "*") {
+					trimmed = strings.TrimPrefix(trimmed[1:], " ")
+				}
+
+				lines[i] = trimmed
+			}
+
+			return strings.Join(lines, "\n")
+		}
+	}
+
+	return text
+}
+
+func isLineCommented(text string, marker string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) != 0 && !strings.HasPrefix(line, marker) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isBlockCommented(text string, style CommentStyle) bool {
+	text = strings.TrimSpace(text)
+	return strings.HasPrefix(text, style.BlockStart) &&
+		strings.HasSuffix(text, style.BlockEnd) &&
+		strings.Count(text, style.BlockEnd) == 1
+}
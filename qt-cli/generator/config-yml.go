@@ -4,8 +4,12 @@
 package generator
 
 import (
+	"fmt"
 	"io/fs"
 	"qtcli/util"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -15,6 +19,7 @@ type ConfigData struct {
 	Version string            `yaml:"version"`
 	Files   []ConfigEntryFile `yaml:"files"`
 	Global  ConfigEntryGlobal `yaml:"global"`
+	Strict  bool              `yaml:"strict"`
 }
 
 type ConfigEntryFile struct {
@@ -22,34 +27,232 @@ type ConfigEntryFile struct {
 	Out        string              `yaml:"out"`
 	FieldsList []ConfigEntryFields `yaml:"fields"`
 	When       string              `yaml:"when"`
+	Append     bool                `yaml:"append"`
+
+	// lines of the values, e.g., Lines["out"]
+	Lines map[string]int `yaml:"-"`
 }
 
 type ConfigEntryGlobal struct {
 	FieldsList []ConfigEntryFields `yaml:"fields"`
 	Header     string              `yaml:"header"`
+	Modules    string              `yaml:"modules"`
+
+	// lines of the values, e.g., Lines["header"]
+	Lines map[string]int `yaml:"-"`
+}
+
+func (f *ConfigEntryFile) UnmarshalYAML(node *yaml.Node) error {
+	type plain ConfigEntryFile
+	if err := node.Decode((*plain)(f)); err != nil {
+		return err
+	}
+
+	f.Lines = findValueLines(node)
+	return nil
+}
+
+func (g *ConfigEntryGlobal) UnmarshalYAML(node *yaml.Node) error {
+	type plain ConfigEntryGlobal
+	if err := node.Decode((*plain)(g)); err != nil {
+		return err
+	}
+
+	g.Lines = findValueLines(node)
+	return nil
 }
 
+// maps keys of the mapping to lines where their values start
+func findValueLines(node *yaml.Node) map[string]int {
+	lines := map[string]int{}
+	if node.Kind != yaml.MappingNode {
+		return lines
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		lines[node.Content[i].Value] = findValueLine(node.Content[i+1])
+	}
+
+	return lines
+}
+
+// note,
+// the text of a block scalar starts after the line of | or >
+func findValueLine(value *yaml.Node) int {
+	if value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return value.Line + 1
+	}
+
+	return value.Line
+}
+
+// maps field names to configField
 type ConfigEntryFields util.StringAnyMap
 
-func (g *ConfigEntryFields) expandBy(
-	expander *util.TemplateExpander,
-) (util.StringAnyMap, error) {
-	all := util.StringAnyMap{}
+// YAML tags making a field keep a type other than string, e.g.,
+// Includes: !list '{{ .qArgInclude }}'
+const (
+	FieldTypeList = "!list"
+	FieldTypeBool = "!bool"
+	FieldTypeInt  = "!int"
+)
+
+// a field value, which is a template expression if it is a string,
+// the line defining it and the line where the value starts
+type configField struct {
+	Value     any
+	Type      string
+	Line      int
+	ValueLine int
+}
+
+func (g *ConfigEntryFields) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf(
+			"cannot read fields, expected a mapping, line = %v", node.Line)
+	}
+
+	fields := ConfigEntryFields{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if first, found := fields[key.Value]; found {
+			return fmt.Errorf(
+				"cannot define field twice in a group, given = '%v', "+
+					"line = %v, first defined at line %v",
+				key.Value, key.Line, first.(configField).Line)
+		}
+
+		switch value.Tag {
+		case FieldTypeList, FieldTypeBool, FieldTypeInt:
+			if value.Kind != yaml.ScalarNode {
+				return fmt.Errorf(
+					"cannot read field '%v', expected a string after %v, "+
+						"line = %v", key.Value, value.Tag, value.Line)
+			}
+
+			fields[key.Value] = configField{
+				Value:     value.Value,
+				Type:      value.Tag,
+				Line:      key.Line,
+				ValueLine: findValueLine(value),
+			}
 
-	for name, expr := range *g {
-		if str, ok := expr.(string); ok {
-			expanded, err := expander.Name(name).RunString(str)
-			if err != nil {
-				return util.StringAnyMap{}, err
+		default:
+			var decoded any
+			if err := value.Decode(&decoded); err != nil {
+				return err
 			}
 
-			all[name] = expanded
-		} else {
-			all[name] = expr
+			fields[key.Value] = configField{
+				Value:     decoded,
+				Line:      key.Line,
+				ValueLine: findValueLine(value),
+			}
+		}
+	}
+
+	*g = fields
+	return nil
+}
+
+func (f configField) expandBy(expander *util.TemplateExpander) (any, error) {
+	expr, ok := f.Value.(string)
+	if !ok {
+		return f.Value, nil
+	}
+
+	if len(f.Type) == 0 {
+		return expander.RunString(expr)
+	}
+
+	// e.g., !list '{{ .qArgAdd }}' keeps items with spaces
+	value, ok, err := expander.RunValue(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		return f.convertValue(value)
+	}
+
+	expanded, err := expander.RunString(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.convert(expanded)
+}
+
+// keeps a value of the type, or converts its text otherwise
+func (f configField) convertValue(value any) (any, error) {
+	switch t := value.(type) {
+	case nil:
+		return f.convert("")
+
+	case string:
+		return f.convert(t)
+	}
+
+	switch f.Type {
+	case FieldTypeList:
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			items := []string{}
+			for i := 0; i < v.Len(); i++ {
+				items = append(items, fmt.Sprint(v.Index(i).Interface()))
+			}
+
+			return items, nil
+		}
+
+	case FieldTypeBool:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+
+	case FieldTypeInt:
+		v := reflect.ValueOf(value)
+		if v.CanInt() {
+			return int(v.Int()), nil
+		}
+	}
+
+	return f.convert(fmt.Sprint(value))
+}
+
+// e.g., [A B] -> []string{"A", "B"}, true -> true, 42 -> 42
+//
+// note,
+// a list printed as text is split at spaces
+func (f configField) convert(expanded string) (any, error) {
+	value := strings.TrimSpace(expanded)
+
+	switch f.Type {
+	case FieldTypeList:
+		return unpackList(value), nil
+
+	case FieldTypeBool:
+		switch value {
+		case "true":
+			return true, nil
+		case "false", "":
+			return false, nil
+		}
+
+	case FieldTypeInt:
+		if len(value) == 0 {
+			return 0, nil
+		}
+
+		if number, err := strconv.Atoi(value); err == nil {
+			return number, nil
 		}
 	}
 
-	return all, nil
+	return nil, fmt.Errorf(
+		"cannot convert to %v, given = '%v'", f.Type, expanded)
 }
 
 func readConfig(targetFS fs.FS, filePath string) (ConfigData, error) {
@@ -0,0 +1,513 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"os"
+	"slices"
+	"unicode"
+)
+
+// note,
+// this is not a C++ parser. it only recognizes class bodies and
+// the declarations placed directly in them, which is enough for
+// picking up public methods, slots and properties of a class
+type CppClassDecl struct {
+	Name       string
+	Methods    []string
+	Slots      []string
+	Properties []string
+}
+
+type cppAccess int
+
+const (
+	cppAccessPrivate cppAccess = iota
+	cppAccessProtected
+	cppAccessPublic
+	cppAccessPublicSlots
+	cppAccessSignals
+)
+
+// macros which can prefix a member declaration
+var cppDeclModifierMacros = []string{
+	"Q_INVOKABLE", "Q_SLOT", "Q_SIGNAL", "Q_SCRIPTABLE",
+	"Q_REQUIRED_RESULT", "Q_DECL_DEPRECATED", "Q_ALWAYS_INLINE",
+}
+
+var cppNonFunctionNames = []string{
+	"void", "int", "bool", "char", "short", "long", "float", "double",
+	"unsigned", "signed", "auto", "const", "decltype", "operator",
+}
+
+func ReadCppHeaderFile(path string) ([]CppClassDecl, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return []CppClassDecl{}, err
+	}
+
+	return ScanCppHeader(string(raw)), nil
+}
+
+func ScanCppHeader(source string) []CppClassDecl {
+	tokens := tokenizeCpp(source)
+	all := []CppClassDecl{}
+
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i] != "class" && tokens[i] != "struct" {
+			continue
+		}
+
+		// enum class
+		if i > 0 && tokens[i-1] == "enum" {
+			continue
+		}
+
+		name, bodyStart := findCppClassBody(tokens, i+1)
+		if bodyStart < 0 {
+			continue
+		}
+
+		defaultAccess := cppAccessPrivate
+		if tokens[i] == "struct" {
+			defaultAccess = cppAccessPublic
+		}
+
+		decl, bodyEnd := scanCppClassBody(
+			tokens, name, bodyStart, defaultAccess)
+		all = append(all, decl)
+
+		// nested classes are not of interest
+		i = bodyEnd
+	}
+
+	return all
+}
+
+// returns unique test function names for the class, e.g., for methods
+// 'name', 'setName' and property 'name', {"name", "setName", "nameProperty"}
+func (c CppClassDecl) TestFunctionNames() []string {
+	all := []string{}
+	add := func(name string) {
+		// avoid clashes with the functions QtTest calls implicitly
+		switch name {
+		case "initTestCase", "cleanupTestCase", "init", "cleanup":
+			name += "Method"
+		}
+
+		if !slices.Contains(all, name) {
+			all = append(all, name)
+		}
+	}
+
+	for _, name := range c.Methods {
+		add(name)
+	}
+
+	for _, name := range c.Slots {
+		add(name)
+	}
+
+	for _, name := range c.Properties {
+		add(name + "Property")
+	}
+
+	return all
+}
+
+func findCppClassBody(tokens []string, start int) (string, int) {
+	name := ""
+
+	for i := start; i < len(tokens); i++ {
+		switch tok := tokens[i]; tok {
+		case ";", "(", ")", "=", ">", ",":
+			// forward declaration, template argument or elaborated type
+			return "", -1
+
+		case ":":
+			// base classes follow, skip until the body
+			for j := i + 1; j < len(tokens); j++ {
+				if tokens[j] == ";" {
+					return "", -1
+				}
+
+				if tokens[j] == "{" {
+					return name, j
+				}
+			}
+
+			return "", -1
+
+		case "{":
+			if len(name) == 0 {
+				return "", -1
+			}
+
+			return name, i
+
+		case "final":
+			continue
+
+		default:
+			if isCppIdentifier(tok) {
+				name = tok
+			}
+		}
+	}
+
+	return "", -1
+}
+
+func scanCppClassBody(
+	tokens []string,
+	name string,
+	bodyStart int,
+	access cppAccess,
+) (CppClassDecl, int) {
+	decl := CppClassDecl{Name: name}
+	statement := []string{}
+	depth := 0
+
+	for i := bodyStart + 1; i < len(tokens); i++ {
+		tok := tokens[i]
+
+		if depth > 0 {
+			// inside an inline body or a nested type
+			if tok == "{" {
+				depth++
+			} else if tok == "}" {
+				depth--
+				if depth == 0 && slices.Contains(statement, "(") {
+					// inline function body ends a declaration
+					decl.addDeclaration(statement, access)
+					statement = []string{}
+				}
+			}
+
+			continue
+		}
+
+		switch {
+		case (tok == "{" || tok == "}") && isCppInitializerOpen(statement),
+			tok == "{" && isCppMemberInitializer(statement):
+			// e.g., a default argument, f(T t = T{}), or a member
+			// initializer, Foo() : m_x{0} {}
+			statement = append(statement, tok)
+
+		case tok == "}":
+			decl.addDeclaration(statement, access)
+			return decl, i
+
+		case tok == "{":
+			depth++
+			if isCppTypeStatement(statement) {
+				statement = []string{}
+			}
+
+		case tok == ";":
+			decl.addDeclaration(statement, access)
+			statement = []string{}
+
+		case len(statement) == 0 && isCppAccessSpecifier(tokens, i):
+			access, i = readCppAccessSpecifier(tokens, i)
+
+		case len(statement) == 0 && isCppStandaloneMacro(tok):
+			end := i
+			if i+1 < len(tokens) && tokens[i+1] == "(" {
+				end = findCppClosingParen(tokens, i+1)
+			}
+
+			if tok == "Q_PROPERTY" {
+				decl.addProperty(tokens[i+1 : end+1])
+			}
+
+			i = end
+
+		default:
+			statement = append(statement, tok)
+		}
+	}
+
+	return decl, len(tokens)
+}
+
+func (c *CppClassDecl) addDeclaration(statement []string, access cppAccess) {
+	if access != cppAccessPublic && access != cppAccessPublicSlots {
+		return
+	}
+
+	name := findCppFunctionName(statement)
+	if len(name) == 0 || name == c.Name {
+		return
+	}
+
+	if access == cppAccessPublicSlots {
+		c.Slots = append(c.Slots, name)
+	} else {
+		c.Methods = append(c.Methods, name)
+	}
+}
+
+func (c *CppClassDecl) addProperty(args []string) {
+	// (type name READ getter ...)
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "READ", "MEMBER", "WRITE", "BINDABLE":
+			if isCppIdentifier(args[i-1]) {
+				c.Properties = append(c.Properties, args[i-1])
+			}
+			return
+		}
+	}
+}
+
+func findCppFunctionName(statement []string) string {
+	if len(statement) == 0 {
+		return ""
+	}
+
+	switch statement[0] {
+	case "friend", "using", "typedef", "enum", "class", "struct", "union":
+		return ""
+	}
+
+	for i := 1; i < len(statement); i++ {
+		if statement[i] != "(" {
+			continue
+		}
+
+		name := statement[i-1]
+		if !isCppIdentifier(name) ||
+			slices.Contains(cppNonFunctionNames, name) ||
+			slices.Contains(cppDeclModifierMacros, name) {
+			return ""
+		}
+
+		// destructor
+		if i >= 2 && statement[i-2] == "~" {
+			return ""
+		}
+
+		// operators, e.g., operator==(...)
+		if slices.Contains(statement[:i], "operator") {
+			return ""
+		}
+
+		return name
+	}
+
+	return ""
+}
+
+// e.g., true for {"void", "f", "(", "T", "t"}
+func isCppInitializerOpen(statement []string) bool {
+	parens := 0
+	braces := 0
+	for _, tok := range statement {
+		switch tok {
+		case "(":
+			parens++
+		case ")":
+			parens--
+		case "{":
+			braces++
+		case "}":
+			braces--
+		}
+	}
+
+	return parens > 0 || braces > 0
+}
+
+// e.g., true for {"Foo", "(", ")", ":", "m_x"}
+func isCppMemberInitializer(statement []string) bool {
+	last := len(statement) - 1
+	if last < 0 || !isCppIdentifier(statement[last]) && statement[last] != ">" {
+		return false
+	}
+
+	for i := 1; i < last; i++ {
+		if statement[i] == ":" && statement[i-1] == ")" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isCppTypeStatement(statement []string) bool {
+	if len(statement) == 0 {
+		return true
+	}
+
+	switch statement[0] {
+	case "enum", "class", "struct", "union":
+		return true
+	}
+
+	return false
+}
+
+func isCppAccessSpecifier(tokens []string, i int) bool {
+	switch tokens[i] {
+	case "public", "protected", "private", "signals", "Q_SIGNALS":
+		end := i + 1
+		if end < len(tokens) &&
+			(tokens[end] == "slots" || tokens[end] == "Q_SLOTS") {
+			end++
+		}
+
+		return end < len(tokens) && tokens[end] == ":"
+	}
+
+	return false
+}
+
+func readCppAccessSpecifier(tokens []string, i int) (cppAccess, int) {
+	access := cppAccessPrivate
+	switch tokens[i] {
+	case "public":
+		access = cppAccessPublic
+	case "protected":
+		access = cppAccessProtected
+	case "signals", "Q_SIGNALS":
+		access = cppAccessSignals
+	}
+
+	if tokens[i+1] == "slots" || tokens[i+1] == "Q_SLOTS" {
+		if access == cppAccessPublic {
+			access = cppAccessPublicSlots
+		}
+
+		i++
+	}
+
+	// points to ':'
+	return access, i + 1
+}
+
+// e.g., Q_OBJECT, QML_ELEMENT, Q_PROPERTY(...), Q_ENUM(...)
+func isCppStandaloneMacro(tok string) bool {
+	if len(tok) < 2 || !unicode.IsUpper(rune(tok[0])) ||
+		slices.Contains(cppDeclModifierMacros, tok) {
+		return false
+	}
+
+	for _, r := range tok {
+		if !unicode.IsUpper(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func findCppClosingParen(tokens []string, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(tokens) - 1
+}
+
+func isCppIdentifier(tok string) bool {
+	if len(tok) == 0 {
+		return false
+	}
+
+	for i, r := range tok {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// splits C++ source into identifiers, numbers and punctuations,
+// dropping comments, string literals and preprocessor lines
+func tokenizeCpp(source string) []string {
+	tokens := []string{}
+	src := []rune(source)
+	lineStart := true
+
+	for i := 0; i < len(src); i++ {
+		r := src[i]
+
+		switch {
+		case r == '\n':
+			lineStart = true
+			continue
+
+		case unicode.IsSpace(r):
+			continue
+
+		case lineStart && r == '#':
+			// skip until the end of line, honoring continuations
+			for i < len(src) && src[i] != '\n' {
+				if src[i] == '\\' && i+1 < len(src) && src[i+1] == '\n' {
+					i++
+				}
+				i++
+			}
+			continue
+
+		case r == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			i--
+			continue
+
+		case r == '/' && i+1 < len(src) && src[i+1] == '*':
+			i += 2
+			for i+1 < len(src) && !(src[i] == '*' && src[i+1] == '/') {
+				i++
+			}
+			i++
+			continue
+		}
+
+		lineStart = false
+
+		switch {
+		case r == '"' || r == '\'':
+			for i++; i < len(src) && src[i] != r; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			start := i
+			for i+1 < len(src) &&
+				(src[i+1] == '_' ||
+					unicode.IsLetter(src[i+1]) ||
+					unicode.IsDigit(src[i+1])) {
+				i++
+			}
+			tokens = append(tokens, string(src[start:i+1]))
+
+		case r == ':' && i+1 < len(src) && src[i+1] == ':':
+			tokens = append(tokens, "::")
+			i++
+
+		default:
+			tokens = append(tokens, string(r))
+		}
+	}
+
+	return tokens
+}
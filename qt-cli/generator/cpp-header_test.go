@@ -0,0 +1,126 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScanCppHeader(t *testing.T) {
+	source := `
+#include <QObject>
+
+class Foo : public QObject
+{
+    Q_OBJECT
+    Q_PROPERTY(QString name READ name WRITE setName NOTIFY nameChanged)
+
+public:
+    explicit Foo(QObject *parent = nullptr);
+    ~Foo() override;
+
+    QString name() const { return m_name; }
+    void setName(const QString &name);
+    void reset(Options options = Options{}) const { m_options = {}; }
+    int count() const;
+
+signals:
+    void nameChanged();
+
+public slots:
+    void refresh();
+
+private:
+    void update();
+    QString m_name;
+};
+
+enum class Mode { A, B };
+`
+
+	got := ScanCppHeader(source)
+	if len(got) != 1 {
+		t.Fatalf("got %v classes, want 1", len(got))
+	}
+
+	want := CppClassDecl{
+		Name:       "Foo",
+		Methods:    []string{"name", "setName", "reset", "count"},
+		Slots:      []string{"refresh"},
+		Properties: []string{"name"},
+	}
+
+	if got[0].Name != want.Name ||
+		!slices.Equal(got[0].Methods, want.Methods) ||
+		!slices.Equal(got[0].Slots, want.Slots) ||
+		!slices.Equal(got[0].Properties, want.Properties) {
+		t.Errorf("got %+v, want %+v", got[0], want)
+	}
+}
+
+// access specifiers after an inline body are still recognized
+func TestScanCppHeaderInlineBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name: "before signals",
+			source: "class Foo {\npublic:\n" +
+				"    void f(T t = T{}) const { g(); }\n" +
+				"signals:\n    void changed();\n};\n",
+			want: []string{"f"},
+		},
+		{
+			name: "before private",
+			source: "class Foo {\npublic:\n" +
+				"    void f(T t = T{}) const { g(); }\n" +
+				"private:\n    void hidden();\n};\n",
+			want: []string{"f"},
+		},
+		{
+			name: "before another method",
+			source: "struct Foo {\n" +
+				"    void f(T t = T{1, 2}) { }\n" +
+				"    void g();\n};\n",
+			want: []string{"f", "g"},
+		},
+		{
+			name: "after member initializers",
+			source: "class Foo {\npublic:\n" +
+				"    Foo() : m_x{0}, m_y(1), m_z{{1, 2}} { }\n" +
+				"    int x() const;\n" +
+				"private:\n    void hidden();\n};\n",
+			want: []string{"x"},
+		},
+	}
+
+	for _, test := range tests {
+		got := ScanCppHeader(test.source)
+		if len(got) != 1 {
+			t.Errorf("%v: got %v classes, want 1", test.name, len(got))
+			continue
+		}
+
+		if !slices.Equal(got[0].Methods, test.want) {
+			t.Errorf("%v: got %v, want %v", test.name, got[0].Methods, test.want)
+		}
+	}
+}
+
+func TestTestFunctionNames(t *testing.T) {
+	decl := CppClassDecl{
+		Methods:    []string{"name", "setName", "init"},
+		Slots:      []string{"name", "refresh"},
+		Properties: []string{"name"},
+	}
+
+	got := decl.TestFunctionNames()
+	want := []string{"name", "setName", "initMethod", "refresh", "nameProperty"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+)
+
+// variables templates can read unless templates are trusted
+var DefaultAllowedEnv = []string{"HOME", "USER", "USERNAME", "LOGNAME", "LANG"}
+
+// decides which environment variables templates can read via qEnv,
+// and records the ones read
+type EnvPolicy struct {
+	// names or patterns, e.g., MYAPP_*, in addition to the defaults
+	Allowed []string
+
+	// any variable can be read
+	Trusted bool
+
+	read []string
+}
+
+func NewEnvPolicy(allowed []string, trusted bool) *EnvPolicy {
+	return &EnvPolicy{
+		Allowed: allowed,
+		Trusted: trusted,
+	}
+}
+
+// returns the value of the variable, or an error if it is not allowed.
+// a nil policy allows the default variables only.
+func (p *EnvPolicy) Getenv(name string) (string, error) {
+	if p == nil {
+		p = &EnvPolicy{}
+	}
+
+	if !p.Trusted && !p.IsAllowed(name) {
+		return "", fmt.Errorf(
+			"cannot read environment variable, given = '%v', "+
+				"allow it with --allow-env or use --trust-templates", name)
+	}
+
+	if !slices.Contains(p.read, name) {
+		p.read = append(p.read, name)
+	}
+
+	return os.Getenv(name), nil
+}
+
+func (p *EnvPolicy) IsAllowed(name string) bool {
+	for _, pattern := range slices.Concat(DefaultAllowedEnv, p.Allowed) {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returns names of variables read so far, sorted
+func (p *EnvPolicy) ReadNames() []string {
+	if p == nil {
+		return []string{}
+	}
+
+	names := slices.Clone(p.read)
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,299 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"qtcli/util"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// expands fields of the groups in the order of references between them,
+// so that a field can refer to any other field regardless of its group.
+// data holds fields expanded before, e.g., qArg* and global fields, which
+// a field of the same name overrides. returns data with the fields added.
+//
+// note,
+// a field defined again in a later group overrides the earlier one, so
+// the groups before it are expanded first, and the later definition can
+// refer to the earlier value, e.g., FileName: '{{ .FileName }}.h'
+func (g *Generator) expandFieldGroups(
+	groups []ConfigEntryFields,
+	data util.StringAnyMap,
+) (util.StringAnyMap, error) {
+	all := data
+	batch := []ConfigEntryFields{}
+
+	for _, group := range groups {
+		if redefinesField(batch, group) {
+			expanded, err := g.expandFields(batch, all)
+			if err != nil {
+				return expanded, err
+			}
+
+			all = expanded
+			batch = []ConfigEntryFields{}
+		}
+
+		batch = append(batch, group)
+	}
+
+	return g.expandFields(batch, all)
+}
+
+func redefinesField(groups []ConfigEntryFields, group ConfigEntryFields) bool {
+	for name := range group {
+		for _, other := range groups {
+			if _, found := other[name]; found {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (g *Generator) expandFields(
+	groups []ConfigEntryFields,
+	data util.StringAnyMap,
+) (util.StringAnyMap, error) {
+	all := util.StringAnyMap{}
+	all.Merge(data)
+
+	fields := map[string]configField{}
+	names := []string{}
+
+	for _, group := range groups {
+		for name, value := range group {
+			fields[name] = value.(configField)
+			names = append(names, name)
+		}
+	}
+
+	// note, independent fields are expanded in the order of definitions
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(fields[a].Line, fields[b].Line), strings.Compare(a, b))
+	})
+
+	deps := map[string][]string{}
+
+	for _, name := range names {
+		expr, ok := fields[name].Value.(string)
+		if !ok {
+			continue
+		}
+
+		refs, err := findFieldReferences(name, expr, g.GlobalContext.Funcs)
+		if err != nil {
+			return all, g.locateFieldError(fields[name],
+				util.LocateTemplateError(err, expr,
+					[]util.SourceSpan{g.configSource(fields[name].ValueLine)}))
+		}
+
+		for _, ref := range refs {
+			_, inData := data[ref]
+			_, inFields := fields[ref]
+
+			switch {
+			// e.g., FileName: '{{ .FileName }}.h' overrides a global field
+			case ref == name && inData:
+				continue
+
+			case inFields:
+				deps[name] = append(deps[name], ref)
+
+			case !inData:
+				return all, g.fieldError(fields[name].Line,
+					"cannot find field, given = '%v', referred by '%v'",
+					ref, name)
+			}
+		}
+	}
+
+	order, err := g.sortFields(names, fields, deps)
+	if err != nil {
+		return all, err
+	}
+
+	expander := g.newExpander()
+
+	for _, name := range order {
+		field := fields[name]
+		value, err := field.expandBy(expander.
+			Name(name).
+			Data(all).
+			Sources(g.configSource(field.ValueLine)))
+		if err != nil {
+			return all, g.locateFieldError(field, err)
+		}
+
+		all[name] = value
+	}
+
+	return all, nil
+}
+
+// returns names so that each field comes after the fields it refers to
+func (g *Generator) sortFields(
+	names []string,
+	fields map[string]configField,
+	deps map[string][]string,
+) ([]string, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	order := []string{}
+	states := map[string]int{}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch states[name] {
+		case visited:
+			return nil
+
+		case visiting:
+			cycle := slices.Concat(path[slices.Index(path, name):], []string{name})
+			return g.fieldError(fields[name].Line,
+				"cannot expand fields referring to each other, given = '%v'",
+				strings.Join(cycle, " -> "))
+		}
+
+		states[name] = visiting
+		for _, dep := range deps[name] {
+			if err := visit(dep, slices.Concat(path, []string{name})); err != nil {
+				return err
+			}
+		}
+
+		states[name] = visited
+		order = append(order, name)
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, []string{}); err != nil {
+			return []string{}, err
+		}
+	}
+
+	return order, nil
+}
+
+// e.g., templates/classes/cpp/config.yml:12: cannot ...
+func (g *Generator) fieldError(line int, format string, args ...any) error {
+	return fmt.Errorf("%v:%v: %v",
+		g.sourcePath(g.Config.FilePath), line, fmt.Sprintf(format, args...))
+}
+
+// returns the error as it is if it refers to a source line already,
+// or refers to the line defining the field
+func (g *Generator) locateFieldError(field configField, err error) error {
+	var sourceErr util.SourceError
+	if errors.As(err, &sourceErr) {
+		return err
+	}
+
+	return g.fieldError(field.Line, "%v", err)
+}
+
+// returns names of fields the expression refers to, e.g.,
+// '{{ .ClassName | qLower }}.h' -> [ClassName]
+func findFieldReferences(
+	name string,
+	expr string,
+	funcs template.FuncMap,
+) ([]string, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Parse(expr)
+	if err != nil {
+		return []string{}, err
+	}
+
+	refs := []string{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collectFieldReferences(t.Tree.Root, false, &refs)
+		}
+	}
+
+	return refs, nil
+}
+
+// note,
+// in range and with, the dot is another value, so only $.Name is
+// a reference to a field there
+func collectFieldReferences(node parse.Node, inner bool, refs *[]string) {
+	add := func(name string) {
+		if !slices.Contains(*refs, name) {
+			*refs = append(*refs, name)
+		}
+	}
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			collectFieldReferences(child, inner, refs)
+		}
+
+	case *parse.ActionNode:
+		collectFieldReferences(n.Pipe, inner, refs)
+
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+
+		for _, cmd := range n.Cmds {
+			collectFieldReferences(cmd, inner, refs)
+		}
+
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectFieldReferences(arg, inner, refs)
+		}
+
+	case *parse.ChainNode:
+		collectFieldReferences(n.Node, inner, refs)
+
+	case *parse.FieldNode:
+		if !inner {
+			add(n.Ident[0])
+		}
+
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			add(n.Ident[1])
+		}
+
+	case *parse.IfNode:
+		collectFieldReferences(n.Pipe, inner, refs)
+		collectFieldReferences(n.List, inner, refs)
+		collectFieldReferences(n.ElseList, inner, refs)
+
+	case *parse.RangeNode:
+		collectFieldReferences(n.Pipe, inner, refs)
+		collectFieldReferences(n.List, true, refs)
+		collectFieldReferences(n.ElseList, inner, refs)
+
+	case *parse.WithNode:
+		collectFieldReferences(n.Pipe, inner, refs)
+		collectFieldReferences(n.List, true, refs)
+		collectFieldReferences(n.ElseList, inner, refs)
+
+	case *parse.TemplateNode:
+		collectFieldReferences(n.Pipe, inner, refs)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"qtcli/util"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func expandTestFields(source string) (util.StringAnyMap, error) {
+	groups := []ConfigEntryFields{}
+	if err := yaml.Unmarshal([]byte(source), &groups); err != nil {
+		return util.StringAnyMap{}, err
+	}
+
+	g := &Generator{}
+	g.Config.FilePath = "config.yml"
+	g.GlobalContext.Funcs = createGeneralFuncMap(nil)
+
+	return g.expandFieldGroups(groups, util.StringAnyMap{"qArgName": "Foo"})
+}
+
+func TestExpandFieldGroups(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   map[string]string
+	}{
+		{
+			name: "referring to a later group",
+			source: "- Header: '{{ .Base }}.h'\n" +
+				"- Base: '{{ .qArgName | qLower }}'\n",
+			want: map[string]string{"Header": "foo.h", "Base": "foo"},
+		},
+		{
+			name: "later group wins",
+			source: "- Name: a\n" +
+				"- Name: b\n",
+			want: map[string]string{"Name": "b"},
+		},
+		{
+			name: "referring to the earlier value",
+			source: "- Name: '{{ .qArgName }}'\n" +
+				"  Before: '{{ .Name }}'\n" +
+				"- Name: '{{ .Name }}Impl'\n" +
+				"- After: '{{ .Name }}'\n",
+			want: map[string]string{
+				"Name": "FooImpl", "Before": "Foo", "After": "FooImpl",
+			},
+		},
+		{
+			name:   "overriding data",
+			source: "- qArgName: '{{ .qArgName }}Impl'\n",
+			want:   map[string]string{"qArgName": "FooImpl"},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := expandTestFields(test.source)
+		if err != nil {
+			t.Errorf("%v: unexpected error, %v", test.name, err)
+			continue
+		}
+
+		for name, value := range test.want {
+			if got[name] != value {
+				t.Errorf("%v: %v: got %q, want %q", test.name, name, got[name], value)
+			}
+		}
+	}
+}
+
+func TestExpandFieldGroupsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "defined twice in a group",
+			source: "- Name: a\n  Other: b\n  Name: c\n",
+			want: "cannot define field twice in a group, given = 'Name', " +
+				"line = 3, first defined at line 1",
+		},
+		{
+			name:   "referring to each other",
+			source: "- A: '{{ .B }}'\n  B: '{{ .A }}'\n",
+			want:   "cannot expand fields referring to each other",
+		},
+		{
+			name:   "referring to an undefined field",
+			source: "- A: '{{ .Missing }}'\n",
+			want:   "config.yml:1: cannot find field, given = 'Missing'",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := expandTestFields(test.source)
+		if err == nil {
+			t.Errorf("%v: expected an error", test.name)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got '%v', want '%v'", test.name, err, test.want)
+		}
+	}
+}
@@ -11,7 +11,10 @@ import (
 	"unicode"
 )
 
-type CppFuncs struct{}
+type CppFuncs struct {
+	licenseData util.StringAnyMap
+	env         *EnvPolicy
+}
 
 func (cpp CppFuncs) ExtractClassName(fqcn string) string {
 	return extractClassNameOnly(fqcn)
@@ -39,6 +42,25 @@ func (cpp CppFuncs) CreateNamespaceClosings(fqcn string) string {
 	return strings.Join(output, "\n")
 }
 
+// e.g., (MyObject, snake_case, .hpp) -> my_object.hpp.
+// an empty style keeps the name as is.
+func (cpp CppFuncs) CreateFileName(
+	className string, style string, ext string) string {
+	switch style {
+	case FileNamingLowercase:
+		className = strings.ToLower(className)
+
+	case FileNamingSnakeCase:
+		className = toSnakeCase(className)
+	}
+
+	if len(ext) != 0 && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	return className + ext
+}
+
 func (cpp CppFuncs) CreateHeaderGuard(fileName string) string {
 	return strings.ReplaceAll(strings.ToUpper(fileName), ".", "_")
 }
@@ -75,38 +97,33 @@ func (cpp CppFuncs) CreateLicense(
 	className string,
 	fileName string,
 ) string {
-	str, _ := generateLicense(licenseTemplatePath, util.StringAnyMap{
+	data := util.StringAnyMap{}
+	data.Merge(cpp.licenseData)
+	data.Merge(util.StringAnyMap{
 		"ClassName": className,
 		"FileName":  fileName,
 	})
 
+	str, _ := generateLicense(licenseTemplatePath, data, cpp.env)
+	if style, found := FindCommentStyle(fileName); found {
+		str = style.Comment(str)
+	}
+
 	return str
 }
 
+// e.g., ("QWidget", ["QLabel"]) -> [Widgets]
+func (cpp CppFuncs) FindQtComponents(
+	baseClass string, includes []string) []string {
+	return findQtComponents(append([]string{baseClass}, includes...))
+}
+
 func mightBeQtClass(name string) bool {
 	return len(name) >= 2 &&
 		name[0] == 'Q' &&
 		unicode.IsUpper(rune(name[1]))
 }
 
-func findModuleName(name string) string {
-	switch name {
-	case "QObject", "QSharedData":
-		return "QtCore"
-
-	case "QWidget", "QMainWindow":
-		return "QtWidgets"
-
-	case "QQuickItem":
-		return "QtQuick"
-
-	case "QQmlEngine":
-		return "QtQml"
-	}
-
-	return ""
-}
-
 func extractClassNameOnly(fqcn string) string {
 	splits := strings.Split(fqcn, "::")
 	if len(splits) == 0 {
@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
@@ -8,10 +9,49 @@ import (
 	"text/template"
 )
 
-func createGeneralFuncMap() template.FuncMap {
+func createGeneralFuncMap(env *EnvPolicy) template.FuncMap {
 	return template.FuncMap{
-		"qEnv": func(name string) string {
-			return os.Getenv(name)
+		"qEnv": env.Getenv,
+
+		"qLower": func(input string) string {
+			return strings.ToLower(input)
+		},
+
+		// MyObject -> my_object
+		"qSnakeCase": toSnakeCase,
+
+		// MyObject -> MY_OBJECT
+		"qUpperSnakeCase": toUpperSnakeCase,
+
+		// MyObject -> my-object
+		"qKebabCase": toKebabCase,
+
+		// my_object -> myObject
+		"qCamelCase": toCamelCase,
+
+		// my_object -> MyObject
+		"qPascalCase": toPascalCase,
+
+		// ContactEntry -> ContactEntries
+		"qPlural": toPlural,
+
+		// my-object -> my_object
+		"qIdentifier": toIdentifier,
+
+		"qFileExists": func(path string) string {
+			if _, err := os.Stat(path); err == nil {
+				return "true"
+			}
+
+			return ""
+		},
+
+		"qBaseName": func(path string) string {
+			return filepath.Base(path)
+		},
+
+		"qTrimExtension": func(path string) string {
+			return strings.TrimSuffix(path, filepath.Ext(path))
 		},
 
 		"qJoin": func(items []string, sep string) string {
@@ -37,22 +77,16 @@ func createGeneralFuncMap() template.FuncMap {
 			}
 		},
 
-		"qUnpack": func(input string) []string {
-			// [AAA BBB] -> []string{"AAA", "BBB"}
-			input = strings.TrimSpace(input)
-			if len(input) == 0 {
+		// note, a !list field is already a list
+		"qUnpack": func(input any) []string {
+			switch t := input.(type) {
+			case nil:
 				return []string{}
+			case []string:
+				return t
 			}
 
-			if strings.HasPrefix(input, "[") && strings.HasSuffix(input, "]") {
-				if len(input) == 2 {
-					return []string{}
-				}
-
-				return strings.Split(input[1:len(input)-1], " ")
-			}
-
-			return []string{input}
+			return unpackList(fmt.Sprint(input))
 		},
 
 		"qEnsureExtension": func(filename string, ext string) string {
@@ -65,3 +99,21 @@ func createGeneralFuncMap() template.FuncMap {
 		},
 	}
 }
+
+// [AAA BBB] -> []string{"AAA", "BBB"}
+func unpackList(input string) []string {
+	input = strings.TrimSpace(input)
+	if len(input) == 0 {
+		return []string{}
+	}
+
+	if strings.HasPrefix(input, "[") && strings.HasSuffix(input, "]") {
+		if len(input) == 2 {
+			return []string{}
+		}
+
+		return strings.Split(input[1:len(input)-1], " ")
+	}
+
+	return []string{input}
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"strings"
+	"unicode"
+)
+
+type ModelFuncs struct{}
+
+type ModelRole struct {
+	Name     string
+	EnumName string
+	Offset   int
+}
+
+// e.g., []string{"name", "display-name"} ->
+// {"name", "NameRole", 1}, {"display-name", "DisplayNameRole", 2}
+func (m ModelFuncs) CreateRoles(names []string) []ModelRole {
+	all := []ModelRole{}
+
+	for _, name := range names {
+		if len(name) == 0 {
+			continue
+		}
+
+		all = append(all, ModelRole{
+			Name:     name,
+			EnumName: createRoleEnumName(name),
+			Offset:   len(all) + 1,
+		})
+	}
+
+	return all
+}
+
+func createRoleEnumName(name string) string {
+	var builder strings.Builder
+	upperNext := true
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+			continue
+		}
+
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String() + "Role"
+}
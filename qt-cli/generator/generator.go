@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"qtcli/assets"
 	"qtcli/util"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -21,6 +22,9 @@ type Generator struct {
 	TypeConst     TargetType
 	Config        GeneratorConfig
 	GlobalContext GeneratorContext
+
+	env         *EnvPolicy
+	missingKeys []string
 }
 
 // note,
@@ -31,7 +35,20 @@ type GeneratorInputData struct {
 	Name              string
 	OutputDir         string
 	LicenseFile       string
+	License           string
 	CustomTemplateDir string
+	Author            string
+	Email             string
+	Organization      string
+	Reuse             bool
+	AllowedEnv        []string
+	TrustTemplates    bool
+
+	AllowSiblingOutput bool
+
+	// fail on keys missing in the data, or report them after a run
+	Strict          bool
+	WarnMissingKeys bool
 
 	CppBaseClass      string
 	CppMacroList      []string
@@ -39,6 +56,22 @@ type GeneratorInputData struct {
 	CppClassIsQObject bool
 	CppUsePragma      bool
 
+	CppFileNaming      string
+	CppHeaderExtension string
+	CppSourceExtension string
+
+	ModelRoleList []string
+
+	FormEmbedding   string
+	FormUseUiLoader bool
+	FormUiFile      string
+	FormUiClass     string
+	FormSlots       []FormSlot
+
+	TestCaseList   []string
+	TestUsePytest  bool
+	TestHeaderFile string
+
 	PythonBaseClass  string
 	PythonModuleName string
 	PythonImportList []string
@@ -59,11 +92,19 @@ type GeneratorContext struct {
 
 type GeneratorResult struct {
 	FileNames []string
+	QtModules []string
+
+	// environment variables read by templates
+	EnvNames []string
+
+	// keys templates refer to but not defined, e.g., file.h.tmpl:12: Foo
+	MissingKeys []string
 }
 
 func NewGenerator(input *GeneratorInputData) *Generator {
 	return &Generator{
 		GeneratorInputData: *input,
+		env:                NewEnvPolicy(input.AllowedEnv, input.TrustTemplates),
 	}
 }
 
@@ -104,7 +145,47 @@ func (g *Generator) Run() (GeneratorResult, error) {
 
 	}
 
-	return GeneratorResult{FileNames: generateFiles}, nil
+	modules, err := g.evalModules()
+	if err != nil {
+		return GeneratorResult{}, err
+	}
+
+	if g.Reuse && len(g.OutputDir) != 0 {
+		created, err := WriteLicenseTexts(g.License, g.OutputDir)
+		if err != nil {
+			return GeneratorResult{}, err
+		}
+
+		for _, path := range created {
+			logrus.Debug(fmt.Sprintf("created license text %v", path))
+		}
+	}
+
+	return GeneratorResult{
+		FileNames:   generateFiles,
+		QtModules:   modules,
+		EnvNames:    g.env.ReadNames(),
+		MissingKeys: g.missingKeys,
+	}, nil
+}
+
+// returns CMake component names of Qt modules the generated files require
+func (g *Generator) evalModules() ([]string, error) {
+	modules := g.Config.Contents.Global.Modules
+	if len(modules) == 0 {
+		return []string{}, nil
+	}
+
+	out, err := g.newExpander().
+		Name("modules").
+		Data(g.GlobalContext.Data).
+		Sources(g.configSource(g.Config.Contents.Global.Lines["modules"])).
+		RunString(modules)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return unpackList(out), nil
 }
 
 func (g *Generator) evalWhenCondition(file ConfigEntryFile) (bool, error) {
@@ -112,10 +193,25 @@ func (g *Generator) evalWhenCondition(file ConfigEntryFile) (bool, error) {
 		return true, nil
 	}
 
-	out, err := util.NewTemplateExpander().
+	if !IsWhenTemplate(file.When) {
+		when, err := EvalWhenExpr(file.When, g.GlobalContext.Data)
+		if err != nil {
+			source := g.configSource(file.Lines["when"])
+			return false, util.SourceError{
+				Path:    source.Path,
+				Line:    source.Line,
+				Message: err.Error(),
+				Excerpt: strings.TrimSpace(file.When),
+			}
+		}
+
+		return when, nil
+	}
+
+	out, err := g.newExpander().
 		Name(file.In).
 		Data(g.GlobalContext.Data).
-		Funcs(g.GlobalContext.Funcs).
+		Sources(g.configSource(file.Lines["when"])).
 		RunString(file.When)
 	if err != nil {
 		return false, err
@@ -140,6 +236,10 @@ func (g *Generator) validate() error {
 			g.Category, g.Type)
 	}
 
+	if err := g.licenseInput().Validate(); err != nil {
+		return err
+	}
+
 	g.Config.FilePath = findConfigPath(g.TypeConst)
 	if len(g.Config.FilePath) == 0 {
 		return fmt.Errorf(
@@ -172,37 +272,53 @@ func (g *Generator) prepareContext() error {
 	logrus.Debug("preparing global context")
 
 	// func
-	g.GlobalContext.Funcs = createGeneralFuncMap()
+	g.GlobalContext.Funcs = createGeneralFuncMap(g.env)
 	g.GlobalContext.Funcs["cpp"] = func() CppFuncs {
-		return CppFuncs{}
+		return CppFuncs{licenseData: g.createLicenseData(), env: g.env}
+	}
+	g.GlobalContext.Funcs["model"] = func() ModelFuncs {
+		return ModelFuncs{}
 	}
+	g.GlobalContext.Funcs["qLicense"] = g.createLicense
 
 	// fields
 	logrus.Debug("processing fields")
-	expander := util.NewTemplateExpander().Funcs(g.GlobalContext.Funcs)
 	accumulatedFields := util.StringAnyMap{
-		"qArgName":        g.Name,
-		"qArgType":        g.Type,
-		"qArgOutputDir":   g.OutputDir,
-		"qArgLicenseFile": g.LicenseFile,
-		"qArgTemplateDir": g.CustomTemplateDir,
-
-		"qArgBase":    g.CppBaseClass,
-		"qArgAdd":     g.CppMacroList,
-		"qArgInclude": g.CppIncludeList,
-		"qArgQObject": g.CppClassIsQObject,
-		"qArgModule":  g.PythonModuleName,
-		"qArgImport":  g.PythonImportList,
-	}
-
-	for _, group := range g.Config.Contents.Global.FieldsList {
-		out, err := group.expandBy(expander.Data(accumulatedFields))
-		if err != nil {
-			return err
-		}
+		"qArgName":         g.Name,
+		"qArgType":         g.Type,
+		"qArgOutputDir":    g.OutputDir,
+		"qArgLicenseFile":  g.LicenseFile,
+		"qArgLicense":      g.License,
+		"qArgTemplateDir":  g.CustomTemplateDir,
+		"qArgAuthor":       g.Author,
+		"qArgEmail":        g.Email,
+		"qArgOrganization": g.Organization,
+		"qArgReuse":        g.Reuse,
+
+		"qArgBase":      g.CppBaseClass,
+		"qArgAdd":       g.CppMacroList,
+		"qArgInclude":   g.CppIncludeList,
+		"qArgQObject":   g.CppClassIsQObject,
+		"qArgNaming":    g.CppFileNaming,
+		"qArgHeaderExt": g.CppHeaderExtension,
+		"qArgSourceExt": g.CppSourceExtension,
+		"qArgModule":    g.PythonModuleName,
+		"qArgImport":    g.PythonImportList,
+		"qArgRole":      g.ModelRoleList,
+		"qArgEmbedding": g.FormEmbedding,
+		"qArgUiLoader":  g.FormUseUiLoader,
+		"qArgFromUi":    g.FormUiFile,
+		"qArgUiClass":   g.FormUiClass,
+		"qArgSlots":     g.FormSlots,
+		"qArgCase":      g.TestCaseList,
+		"qArgPytest":    g.TestUsePytest,
+		"qArgFor":       g.TestHeaderFile,
+	}
 
-		accumulatedFields.Merge(out)
-		logrus.Debug(fmt.Sprintf("expanding fields, %v", accumulatedFields))
+	accumulatedFields, err := g.expandFieldGroups(
+		g.Config.Contents.Global.FieldsList, accumulatedFields)
+	if err != nil {
+		return err
 	}
 
 	g.GlobalContext.Data = accumulatedFields
@@ -216,28 +332,31 @@ func (g *Generator) prepareContext() error {
 
 func (g *Generator) runSingleFile(file ConfigEntryFile) (string, error) {
 	// update fields
-	allFields := util.StringAnyMap{}
-	allFields.Merge(g.GlobalContext.Data)
-	expander := util.NewTemplateExpander().Funcs(g.GlobalContext.Funcs)
-
-	for _, group := range file.FieldsList {
-		localFields, err := group.expandBy(expander.Data(allFields))
-		if err != nil {
-			return "", err
-		}
-
-		allFields.Merge(localFields)
+	allFields, err := g.expandFieldGroups(file.FieldsList, g.GlobalContext.Data)
+	if err != nil {
+		return "", err
 	}
 
+	expander := g.newExpander()
+
 	// expand output file name
 	outputFileName, err := expander.
 		Name(file.In).
 		Data(allFields).
+		Sources(g.configSource(file.Lines["out"])).
 		RunString(file.Out)
 	if err != nil {
 		return "", err
 	}
 
+	destPath := ""
+	if len(g.OutputDir) != 0 {
+		destPath, err = g.resolveOutputPath(outputFileName)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// expand input contents
 	path := filepath.Join(g.Config.BaseDir, file.In)
 	body, err := util.ReadAllFromFS(g.Config.BaseFS, path)
@@ -245,9 +364,21 @@ func (g *Generator) runSingleFile(file ConfigEntryFile) (string, error) {
 		return "", err
 	}
 
+	// note, the header in config.yml comes before the body
+	header := g.GlobalContext.Header
+	sources := []util.SourceSpan{}
+	if headerLines := strings.Count(header, "\n"); headerLines != 0 {
+		source := g.configSource(g.Config.Contents.Global.Lines["header"])
+		source.Lines = headerLines
+		sources = append(sources, source)
+	}
+
+	sources = append(sources, util.SourceSpan{Path: g.sourcePath(path), Line: 1})
+
 	output, err := expander.
-		Name(outputFileName).
-		RunString(g.GlobalContext.Header + string(body))
+		Name(file.In).
+		Sources(sources...).
+		RunString(header + string(body))
 	if err != nil {
 		return "", err
 	}
@@ -256,9 +387,13 @@ func (g *Generator) runSingleFile(file ConfigEntryFile) (string, error) {
 	output = strings.TrimLeft(output, " \t\r\n")
 
 	// save or write to console
-	if len(g.OutputDir) != 0 {
-		destPath := filepath.Join(g.OutputDir, outputFileName)
-		_, err := util.WriteAll([]byte(output), destPath)
+	if len(destPath) != 0 {
+		if file.Append {
+			err = appendToFile(output, destPath)
+		} else {
+			_, err = util.WriteAll([]byte(output), destPath)
+		}
+
 		if err != nil {
 			return "", err
 		}
@@ -268,3 +403,63 @@ func (g *Generator) runSingleFile(file ConfigEntryFile) (string, error) {
 
 	return outputFileName, nil
 }
+
+// returns an expander with the functions of the global context, which
+// fails on or records missing keys as requested
+func (g *Generator) newExpander() *util.TemplateExpander {
+	expander := util.NewTemplateExpander().
+		Funcs(g.GlobalContext.Funcs).
+		Strict(g.Strict || g.Config.Contents.Strict)
+
+	if g.WarnMissingKeys {
+		expander.OnMissingKey(g.recordMissingKey)
+	}
+
+	return expander
+}
+
+// returns the path of a file of templates as given by the user, e.g.,
+// mytemplates/templates/classes/cpp/file.h.tmpl
+func (g *Generator) sourcePath(path string) string {
+	if len(g.CustomTemplateDir) != 0 {
+		return filepath.Join(g.CustomTemplateDir, path)
+	}
+
+	return path
+}
+
+// refers to a value starting at the line of config.yml
+func (g *Generator) configSource(line int) util.SourceSpan {
+	return util.SourceSpan{Path: g.sourcePath(g.Config.FilePath), Line: line}
+}
+
+func (g *Generator) recordMissingKey(missing util.MissingKeyError) {
+	entry := fmt.Sprintf("%v:%v: %v", missing.Template, missing.Line, missing.Key)
+	if !slices.Contains(g.missingKeys, entry) {
+		g.missingKeys = append(g.missingKeys, entry)
+	}
+}
+
+func appendToFile(output string, destPath string) error {
+	existing, err := os.ReadFile(destPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if strings.Contains(string(existing), output) {
+		logrus.Debug(fmt.Sprintf(
+			"skipping append, contents already exist in %v", destPath))
+		return nil
+	}
+
+	if len(existing) != 0 {
+		if !strings.HasSuffix(string(existing), "\n") {
+			output = "\n" + output
+		}
+
+		output = "\n" + output
+	}
+
+	_, err = util.AppendAll([]byte(output), destPath)
+	return err
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --qobject without a base class creates the same class as before
+func TestGenerateCppClassQObject(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		dir := t.TempDir()
+		g := NewGenerator(&GeneratorInputData{
+			Category:          TargetCategoryClass,
+			Type:              "cpp",
+			Name:              "Foo",
+			OutputDir:         dir,
+			Strict:            strict,
+			CppClassIsQObject: true,
+			CppUsePragma:      true,
+		})
+
+		if _, err := g.Run(); err != nil {
+			t.Fatalf("strict = %v: unexpected error, %v", strict, err)
+		}
+
+		header, err := os.ReadFile(filepath.Join(dir, "Foo.h"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		source, err := os.ReadFile(filepath.Join(dir, "Foo.cpp"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wantHeader := "#pragma once\n" + strings.Repeat("\n", 6) +
+			"class Foo\n{\n\npublic:\n    Foo();\n};\n\n\n"
+		if string(header) != wantHeader {
+			t.Errorf("strict = %v: got header %q, want %q",
+				strict, header, wantHeader)
+		}
+
+		wantSource := "#include \"Foo.h\"\n\nFoo::Foo()\n{\n\n}\n"
+		if string(source) != wantSource {
+			t.Errorf("strict = %v: got source %q, want %q",
+				strict, source, wantSource)
+		}
+	}
+}
+
+func TestGenerateReuse(t *testing.T) {
+	tests := []struct {
+		classType string
+		files     []string
+	}{
+		{"cpp", []string{"Foo.h", "Foo.cpp"}},
+		{"python", []string{"Foo.py"}},
+		{"cpp-form", []string{"Foo.h", "Foo.cpp", "Foo.ui"}},
+		{"python-form", []string{"Foo.py", "Foo.ui"}},
+	}
+
+	copyright := fmt.Sprintf(
+		"SPDX-FileCopyrightText: %v Jane Doe", time.Now().Year())
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		g := NewGenerator(&GeneratorInputData{
+			Category:     TargetCategoryClass,
+			Type:         test.classType,
+			Name:         "Foo",
+			OutputDir:    dir,
+			License:      "spdx:MIT",
+			Author:       "Jane Doe",
+			Reuse:        true,
+			CppUsePragma: true,
+		})
+
+		if _, err := g.Run(); err != nil {
+			t.Errorf("%v: unexpected error, %v", test.classType, err)
+			continue
+		}
+
+		for _, name := range test.files {
+			raw, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Errorf("%v: %v", test.classType, err)
+				continue
+			}
+
+			contents := string(raw)
+			if !strings.Contains(contents, copyright) ||
+				!strings.Contains(contents, "SPDX-License-Identifier: MIT") {
+				t.Errorf("%v: no SPDX tags in %v, got %q",
+					test.classType, name, contents)
+			}
+
+			// note, the comment must not come before the XML declaration
+			if filepath.Ext(name) == ".ui" &&
+				!strings.HasPrefix(contents, "<?xml version=\"1.0\" "+
+					"encoding=\"UTF-8\"?>\n<!--\n") {
+				t.Errorf("%v: no comment after the XML declaration, got %q",
+					test.classType, contents)
+			}
+		}
+
+		text := filepath.Join(dir, ReuseLicenseDir, "MIT.txt")
+		if _, err := os.Stat(text); err != nil {
+			t.Errorf("%v: %v", test.classType, err)
+		}
+	}
+}
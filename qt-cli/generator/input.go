@@ -15,6 +15,7 @@ const (
 	TargetCategoryProject TargetCategory = "TargetCategoryProject"
 	TargetCategoryClass   TargetCategory = "TargetCategoryClass"
 	TargetCategoryFile    TargetCategory = "TargetCategoryFile"
+	TargetCategoryTest    TargetCategory = "TargetCategoryTest"
 )
 
 type TargetType string
@@ -23,14 +24,56 @@ const (
 	TargetTypeInvalid TargetType = "TargetTypeInvalid"
 	TargetClassCpp    TargetType = "TargetClassCpp"
 	TargetClassPython TargetType = "TargetClassPython"
+
+	TargetClassCppListModel     TargetType = "TargetClassCppListModel"
+	TargetClassCppTableModel    TargetType = "TargetClassCppTableModel"
+	TargetClassCppTreeModel     TargetType = "TargetClassCppTreeModel"
+	TargetClassPythonListModel  TargetType = "TargetClassPythonListModel"
+	TargetClassPythonTableModel TargetType = "TargetClassPythonTableModel"
+	TargetClassPythonTreeModel  TargetType = "TargetClassPythonTreeModel"
+
+	TargetClassCppForm    TargetType = "TargetClassCppForm"
+	TargetClassPythonForm TargetType = "TargetClassPythonForm"
+
+	TargetTestCpp    TargetType = "TargetTestCpp"
+	TargetTestPython TargetType = "TargetTestPython"
+)
+
+// how C++ file names are derived from a class name
+const (
+	FileNamingAsIs      = "as-is"
+	FileNamingLowercase = "lowercase"
+	FileNamingSnakeCase = "snake_case"
 )
 
+var FileNamingNames = []string{
+	FileNamingAsIs, FileNamingLowercase, FileNamingSnakeCase,
+}
+
+var CppHeaderExtensions = []string{".h", ".hpp", ".hh", ".hxx"}
+var CppSourceExtensions = []string{".cpp", ".cc", ".cxx"}
+
 type SearchDict = map[TargetType][]string
 
 var typeNamesDict = map[TargetCategory]SearchDict{
 	TargetCategoryClass: {
 		TargetClassCpp:    {"cpp"},
 		TargetClassPython: {"python"},
+
+		TargetClassCppListModel:     {"cpp-list-model"},
+		TargetClassCppTableModel:    {"cpp-table-model"},
+		TargetClassCppTreeModel:     {"cpp-tree-model"},
+		TargetClassPythonListModel:  {"python-list-model"},
+		TargetClassPythonTableModel: {"python-table-model"},
+		TargetClassPythonTreeModel:  {"python-tree-model"},
+
+		TargetClassCppForm:    {"cpp-form"},
+		TargetClassPythonForm: {"python-form"},
+	},
+
+	TargetCategoryTest: {
+		TargetTestCpp:    {"cpp"},
+		TargetTestPython: {"python"},
 	},
 }
 
@@ -57,6 +100,36 @@ func findConfigPath(newType TargetType) string {
 
 	case TargetClassPython:
 		return "templates/classes/python/config.yml"
+
+	case TargetClassCppListModel:
+		return "templates/classes/cpp-list-model/config.yml"
+
+	case TargetClassCppTableModel:
+		return "templates/classes/cpp-table-model/config.yml"
+
+	case TargetClassCppTreeModel:
+		return "templates/classes/cpp-tree-model/config.yml"
+
+	case TargetClassPythonListModel:
+		return "templates/classes/python-list-model/config.yml"
+
+	case TargetClassPythonTableModel:
+		return "templates/classes/python-table-model/config.yml"
+
+	case TargetClassPythonTreeModel:
+		return "templates/classes/python-tree-model/config.yml"
+
+	case TargetClassCppForm:
+		return "templates/classes/cpp-form/config.yml"
+
+	case TargetClassPythonForm:
+		return "templates/classes/python-form/config.yml"
+
+	case TargetTestCpp:
+		return "templates/tests/cpp/config.yml"
+
+	case TargetTestPython:
+		return "templates/tests/python/config.yml"
 	}
 
 	return ""
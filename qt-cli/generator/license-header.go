@@ -0,0 +1,187 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	LicenseHeaderAdded     = "added"
+	LicenseHeaderUpdated   = "updated"
+	LicenseHeaderUnchanged = "unchanged"
+)
+
+var (
+	copyrightYearsRegex = regexp.MustCompile(
+		`(?i)(copyright(?:text:)?\s*(?:\(c\)|©)?\s*)(\d{4})(?:\s*-\s*(\d{4}))?`)
+	spdxIdentifierRegex = regexp.MustCompile(`SPDX-License-Identifier:`)
+	pythonCodingRegex   = regexp.MustCompile(`^[ \t\f]*#.*?coding[:=]`)
+)
+
+// adds the header to the contents, or extends the year range in an existing
+// header to the given year, e.g., 2023 -> 2023-2026. a header is one of
+// the comments before the first code with an SPDX identifier or a copyright
+// notice, including SPDX-FileCopyrightText. returns the new contents and
+// one of LicenseHeader*.
+//
+// note,
+// the header goes after a shebang, a python encoding line or
+// an XML declaration. only the first copyright year is updated.
+func ApplyLicenseHeader(
+	contents string,
+	header string,
+	style CommentStyle,
+	year int,
+) (string, string) {
+	// e.g., keeps CRLF of files written on Windows
+	newline := "\n"
+	if index := strings.Index(contents, "\n"); index > 0 &&
+		contents[index-1] == '\r' {
+		newline = "\r\n"
+	}
+
+	text := strings.ReplaceAll(contents, "\r\n", "\n")
+	restoreNewlines := func(s string) string {
+		return strings.ReplaceAll(s, "\n", newline)
+	}
+
+	prologueEnd := findPrologueEnd(text, style)
+	blockStart, block := findHeaderComment(text[prologueEnd:], style)
+	blockStart += prologueEnd
+
+	if len(block) != 0 {
+		updated, changed := updateCopyrightYears(block, year)
+		if !changed {
+			return contents, LicenseHeaderUnchanged
+		}
+
+		text = text[:blockStart] + updated + text[blockStart+len(block):]
+		return restoreNewlines(text), LicenseHeaderUpdated
+	}
+
+	header = strings.ReplaceAll(header, "\r\n", "\n")
+	if len(strings.TrimSpace(header)) == 0 {
+		return contents, LicenseHeaderUnchanged
+	}
+
+	prologue := text[:prologueEnd]
+	if len(prologue) != 0 && !strings.HasSuffix(prologue, "\n") {
+		prologue += "\n"
+	}
+
+	rest := text[prologueEnd:]
+	if len(rest) != 0 && !strings.HasPrefix(rest, "\n") {
+		rest = "\n" + rest
+	}
+
+	text = prologue + strings.TrimRight(header, "\n") + "\n" + rest
+	return restoreNewlines(text), LicenseHeaderAdded
+}
+
+// returns the offset after lines to keep at the top of the file
+func findPrologueEnd(text string, style CommentStyle) int {
+	end := 0
+
+	for i := 0; i < 2 && end < len(text); i++ {
+		line, _, _ := strings.Cut(text[end:], "\n")
+		trimmed := strings.TrimSpace(line)
+
+		isPrologue := (i == 0 && strings.HasPrefix(trimmed, "#!")) ||
+			(style.Line == "#" && pythonCodingRegex.MatchString(line)) ||
+			(i == 0 && strings.HasPrefix(trimmed, "<?xml"))
+		if !isPrologue {
+			break
+		}
+
+		end += len(line)
+		if end < len(text) {
+			end++
+		}
+	}
+
+	return end
+}
+
+// returns the offset and the text of the first comment with an SPDX
+// identifier or a copyright notice among comments at the top, e.g., after
+// a comment describing the file, or an empty string if there is none
+func findHeaderComment(text string, style CommentStyle) (int, string) {
+	offset := 0
+
+	for offset < len(text) {
+		start, block := findLeadingComment(text[offset:], style)
+		if len(block) == 0 {
+			break
+		}
+
+		start += offset
+		if spdxIdentifierRegex.MatchString(block) ||
+			copyrightYearsRegex.MatchString(block) {
+			return start, block
+		}
+
+		offset = start + len(block)
+	}
+
+	return 0, ""
+}
+
+// returns the offset and the text of the comment at the top,
+// skipping empty lines before it
+func findLeadingComment(text string, style CommentStyle) (int, string) {
+	start := len(text) - len(strings.TrimLeft(text, " \t\n"))
+	start = strings.LastIndex(text[:start], "\n") + 1
+	rest := text[start:]
+	trimmed := strings.TrimLeft(rest, " \t")
+
+	if len(style.Line) != 0 && strings.HasPrefix(trimmed, style.Line) {
+		end := 0
+		for end < len(rest) {
+			line, _, _ := strings.Cut(rest[end:], "\n")
+			if !strings.HasPrefix(strings.TrimSpace(line), style.Line) {
+				break
+			}
+
+			end += len(line) + 1
+		}
+
+		return start, rest[:min(end, len(rest))]
+	}
+
+	if len(style.BlockStart) != 0 && strings.HasPrefix(trimmed, style.BlockStart) {
+		index := strings.Index(rest, style.BlockEnd)
+		if index < 0 {
+			return start, ""
+		}
+
+		return start, rest[:index+len(style.BlockEnd)]
+	}
+
+	return start, ""
+}
+
+// extends the first copyright year or year range to the given year
+func updateCopyrightYears(text string, year int) (string, bool) {
+	loc := copyrightYearsRegex.FindStringSubmatchIndex(text)
+	if loc == nil {
+		return text, false
+	}
+
+	first, _ := strconv.Atoi(text[loc[4]:loc[5]])
+	last := first
+	if loc[6] >= 0 {
+		last, _ = strconv.Atoi(text[loc[6]:loc[7]])
+	}
+
+	if last >= year {
+		return text, false
+	}
+
+	years := fmt.Sprintf("%v-%v", first, year)
+	return text[:loc[4]] + years + text[loc[1]:], true
+}
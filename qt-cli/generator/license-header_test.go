@@ -0,0 +1,165 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import "testing"
+
+func TestApplyLicenseHeader(t *testing.T) {
+	const header = "// SPDX-License-Identifier: MIT\n"
+	const hashHeader = "# SPDX-License-Identifier: MIT\n"
+	const xmlHeader = "<!-- SPDX-License-Identifier: MIT -->\n"
+
+	tests := []struct {
+		name     string
+		contents string
+		header   string
+		style    CommentStyle
+		want     string
+		status   string
+	}{
+		{
+			name:     "added on top",
+			contents: "int x;\n",
+			header:   header,
+			style:    commentStyleSlash,
+			want:     "// SPDX-License-Identifier: MIT\n\nint x;\n",
+			status:   LicenseHeaderAdded,
+		},
+		{
+			name:     "added to an empty file",
+			contents: "",
+			header:   header,
+			style:    commentStyleSlash,
+			want:     "// SPDX-License-Identifier: MIT\n",
+			status:   LicenseHeaderAdded,
+		},
+		{
+			name:     "added before a comment without a license",
+			contents: "// helpers\nint x;\n",
+			header:   header,
+			style:    commentStyleSlash,
+			want:     "// SPDX-License-Identifier: MIT\n\n// helpers\nint x;\n",
+			status:   LicenseHeaderAdded,
+		},
+		{
+			name:     "added after a shebang",
+			contents: "#!/usr/bin/env python3\nimport os\n",
+			header:   hashHeader,
+			style:    commentStyleHash,
+			want: "#!/usr/bin/env python3\n# SPDX-License-Identifier: MIT\n" +
+				"\nimport os\n",
+			status: LicenseHeaderAdded,
+		},
+		{
+			name: "added after an encoding line",
+			contents: "#!/usr/bin/env python3\n# -*- coding: utf-8 -*-\n" +
+				"import os\n",
+			header: hashHeader,
+			style:  commentStyleHash,
+			want: "#!/usr/bin/env python3\n# -*- coding: utf-8 -*-\n" +
+				"# SPDX-License-Identifier: MIT\n\nimport os\n",
+			status: LicenseHeaderAdded,
+		},
+		{
+			name:     "added after an XML declaration",
+			contents: "<?xml version=\"1.0\"?>\n<ui/>\n",
+			header:   xmlHeader,
+			style:    commentStyleXml,
+			want: "<?xml version=\"1.0\"?>\n" +
+				"<!-- SPDX-License-Identifier: MIT -->\n\n<ui/>\n",
+			status: LicenseHeaderAdded,
+		},
+		{
+			name:     "added keeping CRLF",
+			contents: "int x;\r\nint y;\r\n",
+			header:   header,
+			style:    commentStyleSlash,
+			want:     "// SPDX-License-Identifier: MIT\r\n\r\nint x;\r\nint y;\r\n",
+			status:   LicenseHeaderAdded,
+		},
+		{
+			name:     "added when a license comment is after code",
+			contents: "int x;\n// Copyright (C) 2026 Jane\n",
+			header:   header,
+			style:    commentStyleSlash,
+			want: "// SPDX-License-Identifier: MIT\n\nint x;\n" +
+				"// Copyright (C) 2026 Jane\n",
+			status: LicenseHeaderAdded,
+		},
+		{
+			name:     "unchanged with an SPDX identifier",
+			contents: "// SPDX-License-Identifier: BSD-3-Clause\nint x;\n",
+			header:   header,
+			style:    commentStyleSlash,
+			want:     "// SPDX-License-Identifier: BSD-3-Clause\nint x;\n",
+			status:   LicenseHeaderUnchanged,
+		},
+		{
+			name:     "unchanged with the current year",
+			contents: "// Copyright (C) 2026 Jane\nint x;\n",
+			header:   header,
+			style:    commentStyleSlash,
+			want:     "// Copyright (C) 2026 Jane\nint x;\n",
+			status:   LicenseHeaderUnchanged,
+		},
+		{
+			name: "unchanged after a comment describing the file",
+			contents: "// file description\n\n// Copyright (C) 2026 Me\n" +
+				"// SPDX-License-Identifier: MIT\n\nint x;\n",
+			header: header,
+			style:  commentStyleSlash,
+			want: "// file description\n\n// Copyright (C) 2026 Me\n" +
+				"// SPDX-License-Identifier: MIT\n\nint x;\n",
+			status: LicenseHeaderUnchanged,
+		},
+		{
+			name: "unchanged in a block comment after other comments",
+			contents: "/* desc */\n// other\n\n/*\n" +
+				" * SPDX-License-Identifier: MIT\n */\nint x;\n",
+			header: header,
+			style:  commentStyleSlash,
+			want: "/* desc */\n// other\n\n/*\n" +
+				" * SPDX-License-Identifier: MIT\n */\nint x;\n",
+			status: LicenseHeaderUnchanged,
+		},
+		{
+			name:     "year extended",
+			contents: "// Copyright (C) 2023 Jane\nint x;\n",
+			header:   header,
+			style:    commentStyleSlash,
+			want:     "// Copyright (C) 2023-2026 Jane\nint x;\n",
+			status:   LicenseHeaderUpdated,
+		},
+		{
+			name:     "year range extended",
+			contents: "# SPDX-FileCopyrightText: 2020 - 2024 Jane\nx = 1\n",
+			header:   hashHeader,
+			style:    commentStyleHash,
+			want:     "# SPDX-FileCopyrightText: 2020-2026 Jane\nx = 1\n",
+			status:   LicenseHeaderUpdated,
+		},
+		{
+			name: "year extended after a comment describing the file",
+			contents: "// file description\n\n// Copyright (C) 2024 Me\n" +
+				"int x;\n",
+			header: header,
+			style:  commentStyleSlash,
+			want: "// file description\n\n// Copyright (C) 2024-2026 Me\n" +
+				"int x;\n",
+			status: LicenseHeaderUpdated,
+		},
+	}
+
+	for _, test := range tests {
+		got, status := ApplyLicenseHeader(
+			test.contents, test.header, test.style, 2026)
+		if got != test.want {
+			t.Errorf("%v: got %q, want %q", test.name, got, test.want)
+		}
+
+		if status != test.status {
+			t.Errorf("%v: got status %v, want %v", test.name, status, test.status)
+		}
+	}
+}
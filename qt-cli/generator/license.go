@@ -4,22 +4,280 @@
 package generator
 
 import (
+	"cmp"
+	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"qtcli/assets"
 	"qtcli/util"
+	"slices"
+	"strings"
 	"text/template"
 	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const SpdxLicensePrefix = "spdx:"
+
+const (
+	spdxLicenseTemplatePath  = "templates/licenses/spdx.tmpl"
+	reuseLicenseTemplatePath = "templates/licenses/reuse.tmpl"
+	licenseTextDir           = "licenses"
 )
 
+// where license texts go in a REUSE compliant project
+const ReuseLicenseDir = "LICENSES"
+
+var SpdxLicenseIds = []string{
+	"MIT", "BSD-3-Clause", "Apache-2.0", "LGPL-3.0-only", "GPL-3.0-only",
+	"LicenseRef-Qt-Commercial",
+}
+
+// deprecated or short names, e.g., spdx:LGPL-3.0
+var spdxLicenseAliases = map[string]string{
+	"LGPL-3.0":      "LGPL-3.0-only",
+	"GPL-3.0":       "GPL-3.0-only",
+	"Qt-Commercial": "LicenseRef-Qt-Commercial",
+}
+
+// e.g., spdx:MIT -> MIT, spdx:Qt-Commercial OR LGPL-3.0 ->
+// LicenseRef-Qt-Commercial OR LGPL-3.0-only
+func ParseSpdxLicense(value string) (string, error) {
+	if !strings.HasPrefix(value, SpdxLicensePrefix) {
+		return "", fmt.Errorf(
+			"invalid license, given = '%v', expected %v<id>",
+			value, SpdxLicensePrefix)
+	}
+
+	ids := []string{}
+	expr := strings.TrimPrefix(value, SpdxLicensePrefix)
+	for _, id := range strings.Split(expr, " OR ") {
+		id = strings.TrimSpace(id)
+		if alias, found := spdxLicenseAliases[id]; found {
+			id = alias
+		}
+
+		if !slices.Contains(SpdxLicenseIds, id) {
+			return "", fmt.Errorf(
+				"unknown SPDX license, given = '%v', expected one of %v",
+				id, SpdxLicenseIds)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return strings.Join(ids, " OR "), nil
+}
+
+// selects the license and the data to fill it with
+type LicenseInput struct {
+	License      string
+	LicenseFile  string
+	Author       string
+	Email        string
+	Organization string
+
+	// SPDX-FileCopyrightText instead of a copyright notice
+	Reuse bool
+
+	// environment variables the license template can read
+	Env *EnvPolicy
+
+	// where git config is looked up
+	Dir string
+}
+
+func (g *Generator) licenseInput() LicenseInput {
+	return LicenseInput{
+		License:      g.License,
+		LicenseFile:  g.LicenseFile,
+		Author:       g.Author,
+		Email:        g.Email,
+		Organization: g.Organization,
+		Reuse:        g.Reuse,
+		Env:          g.env,
+		Dir:          g.OutputDir,
+	}
+}
+
+func (g *Generator) createLicenseData() util.StringAnyMap {
+	return g.licenseInput().createData()
+}
+
+func (g *Generator) createLicense(
+	className string,
+	fileName string,
+) (string, error) {
+	return g.licenseInput().Render(className, fileName)
+}
+
+func (l LicenseInput) Validate() error {
+	if len(l.License) != 0 && len(l.LicenseFile) != 0 {
+		return fmt.Errorf("cannot use both a license and a license file")
+	}
+
+	if l.Reuse && len(l.License) == 0 {
+		return fmt.Errorf("cannot use REUSE tags without an SPDX license")
+	}
+
+	if len(l.License) != 0 {
+		if _, err := ParseSpdxLicense(l.License); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// data available to license templates in addition to dates and the user.
+// values not given are read from git config, e.g., user.name.
+func (l LicenseInput) createData() util.StringAnyMap {
+	dir := l.Dir
+	if len(dir) == 0 {
+		dir = "."
+	}
+
+	identity := util.ReadGitIdentity(dir)
+
+	return util.StringAnyMap{
+		"Author":       cmp.Or(l.Author, identity.Name),
+		"Email":        cmp.Or(l.Email, identity.Email),
+		"Organization": cmp.Or(l.Organization, identity.Organization),
+	}
+}
+
+// renders the selected license as a comment of the file,
+// or returns an empty string if none is selected
+func (l LicenseInput) Render(
+	className string,
+	fileName string,
+) (string, error) {
+	data := l.createData()
+	data.Merge(util.StringAnyMap{
+		"ClassName": className,
+		"FileName":  fileName,
+	})
+
+	var text string
+	var err error
+
+	switch {
+	case len(l.License) != 0:
+		data["LicenseId"], err = ParseSpdxLicense(l.License)
+		if err != nil {
+			return "", err
+		}
+
+		path := spdxLicenseTemplatePath
+		if l.Reuse {
+			path = reuseLicenseTemplatePath
+		}
+
+		raw, err := util.ReadAllFromFS(assets.Assets, path)
+		if err != nil {
+			return "", err
+		}
+
+		text, err = expandLicense(filepath.Base(path), string(raw), data, l.Env)
+		if err != nil {
+			return "", err
+		}
+
+	case len(l.LicenseFile) != 0:
+		text, err = generateLicense(l.LicenseFile, data, l.Env)
+		if err != nil {
+			return "", err
+		}
+
+	default:
+		return "", nil
+	}
+
+	style, found := FindCommentStyle(fileName)
+	if !found {
+		return text, nil
+	}
+
+	return style.Comment(text), nil
+}
+
+// returns LICENSES/<id>.txt missing for licenses of the SPDX expression.
+// LICENSES is in the top directory of the repository containing
+// the directory, or in the directory itself.
+func FindMissingLicenseTexts(license string, dir string) ([]string, error) {
+	missing := []string{}
+
+	expr, err := ParseSpdxLicense(license)
+	if err != nil {
+		return missing, err
+	}
+
+	root := util.FindRepositoryRoot(dir)
+	if len(root) == 0 {
+		root = dir
+	}
+
+	for _, id := range strings.Split(expr, " OR ") {
+		path := filepath.Join(root, ReuseLicenseDir, id+".txt")
+		if _, err := os.Stat(path); err == nil {
+			logrus.Debug(fmt.Sprintf("keeping existing %v", path))
+			continue
+		}
+
+		missing = append(missing, path)
+	}
+
+	return missing, nil
+}
+
+// creates missing license texts, and returns their paths
+func WriteLicenseTexts(license string, dir string) ([]string, error) {
+	missing, err := FindMissingLicenseTexts(license, dir)
+	if err != nil {
+		return []string{}, err
+	}
+
+	for _, path := range missing {
+		raw, err := util.ReadAllFromFS(
+			assets.Assets, licenseTextDir+"/"+filepath.Base(path))
+		if err != nil {
+			return []string{}, err
+		}
+
+		if _, err := util.WriteAll(raw, path); err != nil {
+			return []string{}, err
+		}
+	}
+
+	return missing, nil
+}
+
 func generateLicense(
 	licenseTemplatePath string,
 	data util.StringAnyMap,
+	env *EnvPolicy,
 ) (string, error) {
 	if len(licenseTemplatePath) == 0 {
 		return "", nil
 	}
 
+	raw, err := os.ReadFile(licenseTemplatePath)
+	if err != nil {
+		return "", err
+	}
+
+	return expandLicense(
+		filepath.Base(licenseTemplatePath), string(raw), data, env)
+}
+
+func expandLicense(
+	name string,
+	text string,
+	data util.StringAnyMap,
+	env *EnvPolicy,
+) (string, error) {
 	now := time.Now()
 	user, _ := user.Current()
 	dataAll := util.StringAnyMap{
@@ -32,12 +290,10 @@ func generateLicense(
 	dataAll.Merge(data)
 
 	return util.NewTemplateExpander().
-		Name(filepath.Base(licenseTemplatePath)).
+		Name(name).
 		Data(dataAll).
 		Funcs(template.FuncMap{
-			"qEnv": func(name string) string {
-				return os.Getenv(name)
-			},
+			"qEnv": env.Getenv,
 		}).
-		RunFile(licenseTemplatePath)
+		RunString(text)
 }
@@ -0,0 +1,212 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// singular -> plural, in lowercase
+var irregularPlurals = map[string]string{
+	"child":  "children",
+	"datum":  "data",
+	"foot":   "feet",
+	"index":  "indices",
+	"man":    "men",
+	"matrix": "matrices",
+	"mouse":  "mice",
+	"person": "people",
+	"vertex": "vertices",
+	"woman":  "women",
+}
+
+// splits a name into words at separators and case changes,
+// e.g., MyHTTPServer -> {My, HTTP, Server}, my_object -> {my, object}.
+// digits stay with the preceding word, e.g., Vector3D -> {Vector3D}.
+func splitWords(name string) []string {
+	words := []string{}
+	runes := []rune(name)
+	start := -1
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+		// myObject, HTTPServer, Http2Server
+		boundary := unicode.IsUpper(r) &&
+			(unicode.IsLower(prev) ||
+				(!unicode.IsLower(prev) && nextIsLower))
+
+		if boundary {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
+func toSnakeCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "_"))
+}
+
+func toKebabCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+func toUpperSnakeCase(name string) string {
+	return strings.ToUpper(strings.Join(splitWords(name), "_"))
+}
+
+func toPascalCase(name string) string {
+	result := ""
+	for _, word := range splitCaseWords(name) {
+		result += capitalize(word)
+	}
+
+	return result
+}
+
+func toCamelCase(name string) string {
+	words := splitCaseWords(name)
+	if len(words) == 0 {
+		return ""
+	}
+
+	result := strings.ToLower(words[0])
+	for _, word := range words[1:] {
+		result += capitalize(word)
+	}
+
+	return result
+}
+
+// words of an all uppercase name are lowercased first,
+// e.g., MY_OBJECT -> {my, object}
+func splitCaseWords(name string) []string {
+	words := splitWords(name)
+	if strings.ToUpper(name) != name {
+		return words
+	}
+
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+
+	return words
+}
+
+// note,
+// the rest of the word is kept as is, e.g., HTTP -> HTTP, not Http
+func capitalize(word string) string {
+	if len(word) == 0 {
+		return word
+	}
+
+	runes := []rune(word)
+	return string(unicode.ToUpper(runes[0])) + string(runes[1:])
+}
+
+// pluralizes the last word of the name following simple English rules,
+// e.g., ContactEntry -> ContactEntries, box -> boxes, Child -> Children
+func toPlural(name string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return name
+	}
+
+	last := words[len(words)-1]
+	end := strings.LastIndex(name, last)
+	prefix, suffix := name[:end], name[end+len(last):]
+	lower := strings.ToLower(last)
+
+	if irregular, found := irregularPlurals[lower]; found {
+		return prefix + matchCase(irregular, last) + suffix
+	}
+
+	// an ending is uppercase only for an uppercase word, e.g., BOX -> BOXES
+	upper := strings.ToUpper(last) == last && strings.ToLower(last) != last
+	ending := func(s string) string {
+		if upper {
+			return strings.ToUpper(s)
+		}
+
+		return s
+	}
+
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"),
+		strings.HasSuffix(lower, "z"), strings.HasSuffix(lower, "ch"),
+		strings.HasSuffix(lower, "sh"):
+		last += ending("es")
+
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 &&
+		!strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		last = last[:len(last)-1] + ending("ies")
+
+	default:
+		last += ending("s")
+	}
+
+	return prefix + last + suffix
+}
+
+// applies the case of the given word to the other one,
+// e.g., (children, Child) -> Children, (people, PERSON) -> PEOPLE
+func matchCase(word string, like string) string {
+	switch {
+	case strings.ToUpper(like) == like:
+		return strings.ToUpper(word)
+
+	case unicode.IsUpper([]rune(like)[0]):
+		return capitalize(word)
+	}
+
+	return word
+}
+
+// replaces characters which cannot be in an identifier with '_',
+// e.g., my-object -> my_object, 3d -> _3d
+func toIdentifier(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+			b.WriteRune(r)
+
+		case unicode.IsDigit(r):
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	if b.Len() == 0 {
+		return "_"
+	}
+
+	return b.String()
+}
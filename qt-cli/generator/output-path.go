@@ -0,0 +1,96 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// returns the path to write the file to. the file must be in the output
+// directory, or in its parent directory if siblings are allowed, after
+// resolving symbolic links of existing directories, e.g.,
+// ../include/myobject.h with --allow-sibling-output.
+func (g *Generator) resolveOutputPath(fileName string) (string, error) {
+	if filepath.IsAbs(fileName) || strings.HasPrefix(fileName, "/") ||
+		len(filepath.VolumeName(fileName)) != 0 {
+		return "", fmt.Errorf(
+			"cannot write to an absolute path, given = '%v'", fileName)
+	}
+
+	outputDir, err := filepath.Abs(g.OutputDir)
+	if err != nil {
+		return "", err
+	}
+
+	root := outputDir
+	if g.AllowSiblingOutput {
+		root = filepath.Dir(outputDir)
+	}
+
+	destPath := filepath.Join(outputDir, fileName)
+	if !isWithinDir(root, destPath) {
+		return "", fmt.Errorf(
+			"cannot write outside the output directory, given = '%v'",
+			fileName)
+	}
+
+	realRoot, err := evalExistingSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+
+	realDestPath, err := evalExistingSymlinks(destPath)
+	if err != nil {
+		return "", err
+	}
+
+	if !isWithinDir(realRoot, realDestPath) {
+		return "", fmt.Errorf(
+			"cannot write through a symbolic link outside the output "+
+				"directory, given = '%v', resolved = '%v'",
+			fileName, realDestPath)
+	}
+
+	return destPath, nil
+}
+
+func isWithinDir(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolves symbolic links in the longest existing part of the path, and
+// appends the rest, e.g., a file or directories to be created
+func evalExistingSymlinks(path string) (string, error) {
+	existing := path
+	rest := ""
+
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return path, nil
+		}
+
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(resolved, rest), nil
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"slices"
+	"strings"
+)
+
+// note,
+// this is not a complete list. it covers classes commonly used as
+// a base class or included in a header file.
+var qtModuleClasses = map[string][]string{
+	"QtCore": {
+		"QAbstractItemModel", "QAbstractListModel", "QAbstractTableModel",
+		"QBuffer", "QByteArray", "QCoreApplication", "QDataStream",
+		"QDate", "QDateTime", "QDebug", "QDir", "QElapsedTimer", "QEvent",
+		"QExplicitlySharedDataPointer", "QFile", "QFileInfo",
+		"QIdentityProxyModel", "QIODevice", "QJsonArray", "QJsonDocument",
+		"QJsonObject", "QJsonValue", "QHash", "QList", "QLocale",
+		"QLoggingCategory", "QMap", "QMetaObject", "QMetaType",
+		"QModelIndex", "QMutex", "QObject", "QPoint", "QPointF", "QPointer",
+		"QProcess", "QRect", "QRectF", "QRegularExpression",
+		"QScopedPointer", "QSet", "QSettings", "QSharedData",
+		"QSharedDataPointer", "QSharedPointer", "QSize", "QSizeF",
+		"QSortFilterProxyModel", "QStandardPaths", "QString",
+		"QStringList", "QStringListModel", "QTextStream", "QThread",
+		"QTime", "QTimer", "QUrl", "QUuid", "QVariant", "QVersionNumber",
+	},
+	"QtGui": {
+		"QAction", "QBrush", "QClipboard", "QColor", "QCursor", "QFont",
+		"QFontMetrics", "QGuiApplication", "QIcon", "QImage",
+		"QIntValidator", "QKeyEvent", "QMouseEvent", "QOpenGLFunctions",
+		"QPaintEvent", "QPainter", "QPainterPath", "QPalette", "QPen",
+		"QPixmap", "QRegularExpressionValidator", "QResizeEvent",
+		"QScreen", "QShortcut", "QStandardItem", "QStandardItemModel",
+		"QTextDocument", "QTransform", "QUndoStack", "QValidator",
+		"QWindow",
+	},
+	"QtWidgets": {
+		"QAbstractButton", "QAbstractScrollArea", "QApplication",
+		"QCalendarWidget", "QCheckBox", "QColorDialog", "QComboBox",
+		"QCompleter", "QDateEdit", "QDateTimeEdit", "QDialog",
+		"QDialogButtonBox", "QDockWidget", "QDoubleSpinBox", "QFileDialog",
+		"QFontDialog", "QFormLayout", "QFrame", "QGraphicsItem",
+		"QGraphicsScene", "QGraphicsView", "QGridLayout", "QGroupBox",
+		"QHBoxLayout", "QHeaderView", "QInputDialog", "QLabel",
+		"QLineEdit", "QListView", "QListWidget", "QMainWindow", "QMenu",
+		"QMenuBar", "QMessageBox", "QPlainTextEdit", "QProgressBar",
+		"QPushButton", "QRadioButton", "QScrollArea", "QSizePolicy",
+		"QSlider", "QSpinBox", "QSplitter", "QStackedWidget", "QStatusBar",
+		"QStyledItemDelegate", "QSystemTrayIcon", "QTabWidget",
+		"QTableView", "QTableWidget", "QTextEdit", "QTimeEdit", "QToolBar",
+		"QToolButton", "QTreeView", "QTreeWidget", "QVBoxLayout",
+		"QWidget", "QWizard", "QWizardPage",
+	},
+	"QtQml": {
+		"QJSEngine", "QJSValue", "QQmlApplicationEngine", "QQmlComponent",
+		"QQmlContext", "QQmlEngine", "QQmlEngineExtensionPlugin",
+		"QQmlExtensionPlugin", "QQmlListProperty", "QQmlParserStatus",
+		"QQmlPropertyMap",
+	},
+	"QtQuick": {
+		"QQuickAsyncImageProvider", "QQuickFramebufferObject",
+		"QQuickImageProvider", "QQuickItem", "QQuickPaintedItem",
+		"QQuickRhiItem", "QQuickTextureFactory", "QQuickView",
+		"QQuickWindow", "QSGGeometryNode", "QSGNode",
+	},
+	"QtQuickWidgets": {
+		"QQuickWidget",
+	},
+	"QtNetwork": {
+		"QAbstractSocket", "QDnsLookup", "QHostAddress", "QHttpMultiPart",
+		"QLocalServer", "QLocalSocket", "QNetworkAccessManager",
+		"QNetworkInterface", "QNetworkReply", "QNetworkRequest",
+		"QSslSocket", "QTcpServer", "QTcpSocket", "QUdpSocket",
+	},
+	"QtSql": {
+		"QSqlDatabase", "QSqlError", "QSqlField", "QSqlQuery",
+		"QSqlQueryModel", "QSqlRecord", "QSqlRelationalTableModel",
+		"QSqlTableModel",
+	},
+	"QtTest": {
+		"QSignalSpy", "QTest",
+	},
+	"QtMultimedia": {
+		"QAudioInput", "QAudioOutput", "QCamera", "QMediaCaptureSession",
+		"QMediaPlayer", "QSoundEffect", "QVideoSink",
+	},
+	"QtMultimediaWidgets": {
+		"QVideoWidget",
+	},
+	"QtOpenGL": {
+		"QOpenGLBuffer", "QOpenGLShaderProgram",
+	},
+	"QtOpenGLWidgets": {
+		"QOpenGLWidget",
+	},
+	"QtPrintSupport": {
+		"QPrintDialog", "QPrintPreviewDialog", "QPrinter",
+	},
+	"QtSvg": {
+		"QSvgGenerator", "QSvgRenderer",
+	},
+	"QtSvgWidgets": {
+		"QSvgWidget",
+	},
+	"QtXml": {
+		"QDomDocument", "QDomElement", "QDomNode",
+	},
+	"QtWebSockets": {
+		"QWebSocket", "QWebSocketServer",
+	},
+	"QtSerialPort": {
+		"QSerialPort", "QSerialPortInfo",
+	},
+	"QtDBus": {
+		"QDBusAbstractAdaptor", "QDBusConnection", "QDBusInterface",
+		"QDBusMessage",
+	},
+	"QtUiTools": {
+		"QUiLoader",
+	},
+}
+
+// e.g., QLabel -> QtWidgets
+func findModuleName(name string) string {
+	for module, classes := range qtModuleClasses {
+		if slices.Contains(classes, name) {
+			return module
+		}
+	}
+
+	return ""
+}
+
+// returns CMake component names of Qt modules which the given classes
+// belong to, e.g., {"QObject", "QLabel"} -> {"Core", "Widgets"}
+func findQtComponents(classNames []string) []string {
+	all := []string{}
+
+	for _, name := range classNames {
+		module := findModuleName(name)
+		if len(module) == 0 {
+			continue
+		}
+
+		component := strings.TrimPrefix(module, "Qt")
+		if !slices.Contains(all, component) {
+			all = append(all, component)
+		}
+	}
+
+	return all
+}
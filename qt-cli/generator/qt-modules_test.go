@@ -0,0 +1,30 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindQtComponents(t *testing.T) {
+	tests := []struct {
+		classes []string
+		want    []string
+	}{
+		{[]string{"QObject", "QLabel"}, []string{"Core", "Widgets"}},
+		{[]string{"QOpenGLFunctions"}, []string{"Gui"}},
+		{[]string{"QOpenGLBuffer", "QOpenGLWidget"},
+			[]string{"OpenGL", "OpenGLWidgets"}},
+		{[]string{"QString", "QStringList"}, []string{"Core"}},
+		{[]string{"MyClass", ""}, []string{}},
+	}
+
+	for _, test := range tests {
+		got := findQtComponents(test.classes)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%v: got %v, want %v", test.classes, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type UiForm struct {
+	Class   string
+	Widget  UiObject
+	Objects []UiObject
+}
+
+type UiObject struct {
+	Class string
+	Name  string
+}
+
+type FormSlot struct {
+	Name   string
+	Params []FormSlotParam
+}
+
+type FormSlotParam struct {
+	Type string
+	Name string
+}
+
+type uiSignal struct {
+	Name   string
+	Params []FormSlotParam
+}
+
+// signal used when only an object name is given to --connect
+var uiDefaultSignals = map[string]string{
+	"QAction":            "triggered",
+	"QPushButton":        "clicked",
+	"QToolButton":        "clicked",
+	"QRadioButton":       "clicked",
+	"QCommandLinkButton": "clicked",
+	"QCheckBox":          "toggled",
+	"QDialogButtonBox":   "accepted",
+	"QLineEdit":          "textChanged",
+	"QComboBox":          "currentIndexChanged",
+	"QSpinBox":           "valueChanged",
+	"QDoubleSpinBox":     "valueChanged",
+	"QSlider":            "valueChanged",
+	"QDial":              "valueChanged",
+	"QScrollBar":         "valueChanged",
+}
+
+var uiKnownSignals = []uiSignal{
+	{Name: "toggled", Params: []FormSlotParam{{"bool", "checked"}}},
+	{Name: "textChanged", Params: []FormSlotParam{{"const QString &", "text"}}},
+	{Name: "textEdited", Params: []FormSlotParam{{"const QString &", "text"}}},
+	{Name: "currentIndexChanged", Params: []FormSlotParam{{"int", "index"}}},
+	{Name: "currentTextChanged", Params: []FormSlotParam{{"const QString &", "text"}}},
+}
+
+func ReadUiFile(path string) (UiForm, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return UiForm{}, err
+	}
+
+	defer file.Close()
+
+	form, err := parseUiForm(file)
+	if err != nil {
+		return UiForm{}, fmt.Errorf("cannot parse %v, %v", path, err)
+	}
+
+	return form, nil
+}
+
+func parseUiForm(reader io.Reader) (UiForm, error) {
+	form := UiForm{}
+	decoder := xml.NewDecoder(reader)
+	inClass := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return UiForm{}, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "class":
+				inClass = len(form.Class) == 0 && len(form.Widget.Name) == 0
+
+			case "widget", "action":
+				object := UiObject{
+					Class: findXmlAttr(t, "class"),
+					Name:  findXmlAttr(t, "name"),
+				}
+
+				if t.Name.Local == "action" {
+					object.Class = "QAction"
+				}
+
+				if len(form.Widget.Name) == 0 {
+					form.Widget = object
+				} else if len(object.Name) != 0 {
+					form.Objects = append(form.Objects, object)
+				}
+			}
+
+		case xml.CharData:
+			if inClass {
+				form.Class += strings.TrimSpace(string(t))
+			}
+
+		case xml.EndElement:
+			if t.Name.Local == "class" {
+				inClass = false
+			}
+		}
+	}
+
+	if len(form.Widget.Class) == 0 {
+		return UiForm{}, fmt.Errorf("no top-level widget found")
+	}
+
+	if len(form.Class) == 0 {
+		form.Class = form.Widget.Name
+	}
+
+	return form, nil
+}
+
+// e.g., "okButton" -> on_okButton_clicked(),
+// "checkBox:toggled" -> on_checkBox_toggled(bool checked)
+func (f UiForm) CreateSlots(connections []string) ([]FormSlot, error) {
+	all := []FormSlot{}
+
+	for _, connection := range connections {
+		objectName, signalName, _ := strings.Cut(connection, ":")
+		object, found := f.findObject(objectName)
+		if !found {
+			return []FormSlot{}, fmt.Errorf(
+				"cannot find object '%v' in the form", objectName)
+		}
+
+		if len(signalName) == 0 {
+			signalName = uiDefaultSignals[object.Class]
+			if len(signalName) == 0 {
+				return []FormSlot{}, fmt.Errorf(
+					"cannot determine a signal of '%v' (%v), "+
+						"specify it as '%v:<signal>'",
+					object.Name, object.Class, object.Name)
+			}
+		}
+
+		slot := FormSlot{
+			Name:   fmt.Sprintf("on_%v_%v", object.Name, signalName),
+			Params: findSignalParams(object.Class, signalName),
+		}
+
+		all = append(all, slot)
+	}
+
+	return all, nil
+}
+
+func (f UiForm) findObject(name string) (UiObject, bool) {
+	for _, object := range f.Objects {
+		if object.Name == name {
+			return object, true
+		}
+	}
+
+	return UiObject{}, false
+}
+
+func findSignalParams(className string, signalName string) []FormSlotParam {
+	if signalName == "valueChanged" {
+		if className == "QDoubleSpinBox" {
+			return []FormSlotParam{{"double", "value"}}
+		}
+
+		return []FormSlotParam{{"int", "value"}}
+	}
+
+	for _, signal := range uiKnownSignals {
+		if signal.Name == signalName {
+			return signal.Params
+		}
+	}
+
+	return []FormSlotParam{}
+}
+
+// e.g., on_checkBox_toggled(bool checked)
+func (s FormSlot) CppSignature() string {
+	params := []string{}
+	for _, p := range s.Params {
+		if strings.HasSuffix(p.Type, "&") || strings.HasSuffix(p.Type, "*") {
+			params = append(params, p.Type+p.Name)
+		} else {
+			params = append(params, p.Type+" "+p.Name)
+		}
+	}
+
+	return fmt.Sprintf("%v(%v)", s.Name, strings.Join(params, ", "))
+}
+
+// e.g., "bool" for @Slot(bool)
+func (s FormSlot) PythonSlotTypes() string {
+	types := []string{}
+	for _, p := range s.Params {
+		types = append(types, toPythonType(p.Type))
+	}
+
+	return strings.Join(types, ", ")
+}
+
+// e.g., "self, checked" for def on_checkBox_toggled(self, checked)
+func (s FormSlot) PythonParams() string {
+	params := []string{"self"}
+	for _, p := range s.Params {
+		params = append(params, p.Name)
+	}
+
+	return strings.Join(params, ", ")
+}
+
+func toPythonType(cppType string) string {
+	switch cppType {
+	case "const QString &":
+		return "str"
+	case "double":
+		return "float"
+	}
+
+	return cppType
+}
+
+func findXmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
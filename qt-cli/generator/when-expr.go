@@ -0,0 +1,505 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"fmt"
+	"qtcli/util"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// a condition of 'when' evaluated against fields, e.g.,
+//
+//	when: not qArgPytest
+//	when: UseQSharedData and "QObject" in qArgInclude
+//	when: QtVersion >= v6.5 or (qArgType == "cpp" and not qArgFromUi)
+//
+// note,
+// a condition with {{ is a template instead, which is true if it
+// expands to "true"
+func IsWhenTemplate(when string) bool {
+	return strings.Contains(when, "{{")
+}
+
+func EvalWhenExpr(expr string, data util.StringAnyMap) (bool, error) {
+	tokens, err := tokenizeWhenExpr(expr)
+	if err != nil {
+		return false, err
+	}
+
+	p := whenParser{tokens: tokens, data: data}
+	value, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf(
+			"cannot parse condition, unexpected '%v'", p.tokens[p.pos].text)
+	}
+
+	return isTruthy(value), nil
+}
+
+type whenTokenKind int
+
+const (
+	whenTokenName whenTokenKind = iota
+	whenTokenString
+	whenTokenNumber
+	whenTokenSymbol
+)
+
+type whenToken struct {
+	kind whenTokenKind
+	text string
+}
+
+// symbols, longer ones first
+var whenSymbols = []string{
+	"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ",",
+}
+
+func tokenizeWhenExpr(expr string) ([]whenToken, error) {
+	tokens := []whenToken{}
+	runes := []rune(expr)
+
+	isNameRune := func(r rune) bool {
+		return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			end := i + 1
+			for ; end < len(runes) && runes[end] != r; end++ {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+				}
+
+				b.WriteRune(runes[end])
+			}
+
+			if end >= len(runes) {
+				return []whenToken{}, fmt.Errorf(
+					"cannot parse condition, unterminated string, given = '%v'",
+					string(runes[i:]))
+			}
+
+			tokens = append(tokens, whenToken{whenTokenString, b.String()})
+			i = end + 1
+
+		// e.g., 6.5 or -1
+		case unicode.IsDigit(r) ||
+			(r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			end := i + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) ||
+				runes[end] == '.') {
+				end++
+			}
+
+			number := string(runes[i:end])
+			if r == '-' && strings.Contains(number, ".") {
+				return []whenToken{}, fmt.Errorf(
+					"cannot parse condition, negative version, given = '%v'",
+					number)
+			}
+
+			tokens = append(tokens, whenToken{whenTokenNumber, number})
+			i = end
+
+		case isNameRune(r):
+			end := i
+			for end < len(runes) && isNameRune(runes[end]) {
+				end++
+			}
+
+			// e.g., v6.5 is a version, not a field
+			kind := whenTokenName
+			if name := string(runes[i:end]); strings.HasPrefix(name, "v") &&
+				whenVersionRegex.MatchString(name) {
+				kind = whenTokenNumber
+			}
+
+			tokens = append(tokens, whenToken{kind, string(runes[i:end])})
+			i = end
+
+		default:
+			rest := string(runes[i:])
+			index := slices.IndexFunc(whenSymbols, func(s string) bool {
+				return strings.HasPrefix(rest, s)
+			})
+			if index < 0 {
+				return []whenToken{}, fmt.Errorf(
+					"cannot parse condition, unexpected '%v'", string(r))
+			}
+
+			symbol := whenSymbols[index]
+			tokens = append(tokens, whenToken{whenTokenSymbol, symbol})
+			i += len([]rune(symbol))
+		}
+	}
+
+	return tokens, nil
+}
+
+// note,
+// every operand is evaluated, so that a misspelled field is reported
+// regardless of the other operands
+type whenParser struct {
+	tokens []whenToken
+	pos    int
+	data   util.StringAnyMap
+}
+
+func (p *whenParser) peek() (whenToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return whenToken{}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+// consumes the next token if it is one of the words or symbols
+func (p *whenParser) accept(words ...string) (string, bool) {
+	token, ok := p.peek()
+	if !ok || token.kind == whenTokenString || token.kind == whenTokenNumber {
+		return "", false
+	}
+
+	if !slices.Contains(words, token.text) {
+		return "", false
+	}
+
+	p.pos++
+	return token.text, true
+}
+
+func (p *whenParser) expect(symbol string) error {
+	if _, ok := p.accept(symbol); ok {
+		return nil
+	}
+
+	if token, ok := p.peek(); ok {
+		return fmt.Errorf("cannot parse condition, expected '%v', given = '%v'",
+			symbol, token.text)
+	}
+
+	return fmt.Errorf("cannot parse condition, expected '%v' at the end", symbol)
+}
+
+// or: and ('or' and)*
+func (p *whenParser) parseOr() (any, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("or", "||"); !ok {
+			return left, nil
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = isTruthy(left) || isTruthy(right)
+	}
+}
+
+// and: not ('and' not)*
+func (p *whenParser) parseAnd() (any, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("and", "&&"); !ok {
+			return left, nil
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = isTruthy(left) && isTruthy(right)
+	}
+}
+
+// not: ('not' | '!') not | comparison
+func (p *whenParser) parseNot() (any, error) {
+	if _, ok := p.accept("not", "!"); ok {
+		value, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return !isTruthy(value), nil
+	}
+
+	return p.parseComparison()
+}
+
+// comparison: operand (op operand)?
+func (p *whenParser) parseComparison() (any, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=", "in", "not")
+	if !ok {
+		return left, nil
+	}
+
+	// e.g., "QObject" not in qArgInclude
+	if op == "not" {
+		if _, ok := p.accept("in"); !ok {
+			return nil, fmt.Errorf(
+				"cannot parse condition, expected 'in' after 'not'")
+		}
+
+		op = "not in"
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return compareWhenValues(op, left, right)
+}
+
+// operand: '(' or ')' | '[' operand, ... ']' | string | number | name
+func (p *whenParser) parseOperand() (any, error) {
+	token, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("cannot parse condition, unexpected end")
+	}
+
+	p.pos++
+
+	switch token.kind {
+	case whenTokenString:
+		return token.text, nil
+
+	case whenTokenNumber:
+		if number, err := strconv.Atoi(token.text); err == nil {
+			return number, nil
+		}
+
+		// e.g., 6.5 is a version
+		return token.text, nil
+
+	case whenTokenName:
+		switch token.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "and", "or", "not", "in":
+			return nil, fmt.Errorf(
+				"cannot parse condition, unexpected '%v'", token.text)
+		}
+
+		name := strings.TrimPrefix(token.text, ".")
+		value, found := p.data[name]
+		if !found {
+			return nil, fmt.Errorf("cannot find field, given = '%v'", name)
+		}
+
+		return value, nil
+	}
+
+	switch token.text {
+	case "(":
+		value, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		return value, p.expect(")")
+
+	case "[":
+		items := []string{}
+		if _, ok := p.accept("]"); ok {
+			return items, nil
+		}
+
+		for {
+			item, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, fmt.Sprint(item))
+			if _, ok := p.accept(","); !ok {
+				return items, p.expect("]")
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("cannot parse condition, unexpected '%v'", token.text)
+}
+
+// e.g., a bool field, or a string field from a template
+func isTruthy(value any) bool {
+	switch t := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case int:
+		return t != 0
+	case string:
+		return len(t) != 0 && t != "false"
+	}
+
+	// e.g., []string, []any or a map decoded from yaml
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() != 0
+	}
+
+	return true
+}
+
+func compareWhenValues(op string, left any, right any) (bool, error) {
+	switch op {
+	case "in", "not in":
+		items, ok := toWhenList(right)
+		if !ok {
+			return false, fmt.Errorf(
+				"cannot use '%v' with a value not a list, given = '%v'",
+				op, right)
+		}
+
+		contained := slices.Contains(items, fmt.Sprint(left))
+		return contained == (op == "in"), nil
+
+	case "==", "!=":
+		return whenValuesEqual(left, right) == (op == "=="), nil
+	}
+
+	order, err := compareWhenOrder(left, right)
+	if err != nil {
+		return false, err
+	}
+
+	switch op {
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	}
+
+	return order >= 0, nil
+}
+
+func whenValuesEqual(left any, right any) bool {
+	// e.g., UseUiLoader == true, where the field can be "true" or ""
+	_, leftIsBool := left.(bool)
+	_, rightIsBool := right.(bool)
+	if leftIsBool || rightIsBool {
+		return isTruthy(left) == isTruthy(right)
+	}
+
+	if order, err := compareWhenOrder(left, right); err == nil {
+		return order == 0
+	}
+
+	return fmt.Sprint(left) == fmt.Sprint(right)
+}
+
+// compares versions, e.g., 6.10 > 6.9 and 6.5 == 6.5.0, or strings
+func compareWhenOrder(left any, right any) (int, error) {
+	leftVersion, leftOk := toWhenVersion(left)
+	rightVersion, rightOk := toWhenVersion(right)
+	if leftOk && rightOk {
+		for i := 0; i < max(len(leftVersion), len(rightVersion)); i++ {
+			l, r := 0, 0
+			if i < len(leftVersion) {
+				l = leftVersion[i]
+			}
+
+			if i < len(rightVersion) {
+				r = rightVersion[i]
+			}
+
+			if l != r {
+				return l - r, nil
+			}
+		}
+
+		return 0, nil
+	}
+
+	leftString, leftOk := left.(string)
+	rightString, rightOk := right.(string)
+	if leftOk && rightOk {
+		return strings.Compare(leftString, rightString), nil
+	}
+
+	return 0, fmt.Errorf(
+		"cannot compare values, given = '%v', '%v'", left, right)
+}
+
+// e.g., 6, 6.5, v6.5.0
+var whenVersionRegex = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+
+func toWhenVersion(value any) ([]int, bool) {
+	switch t := value.(type) {
+	case int:
+		return []int{t}, true
+
+	case string:
+		if !whenVersionRegex.MatchString(t) {
+			return []int{}, false
+		}
+
+		version := []int{}
+		for _, part := range strings.Split(strings.TrimPrefix(t, "v"), ".") {
+			number, _ := strconv.Atoi(part)
+			version = append(version, number)
+		}
+
+		return version, true
+	}
+
+	return []int{}, false
+}
+
+func toWhenList(value any) ([]string, bool) {
+	switch t := value.(type) {
+	case []string:
+		return t, true
+
+	case []any:
+		items := []string{}
+		for _, item := range t {
+			items = append(items, fmt.Sprint(item))
+		}
+
+		return items, true
+	}
+
+	return []string{}, false
+}
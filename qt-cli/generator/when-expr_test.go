@@ -0,0 +1,175 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package generator
+
+import (
+	"qtcli/util"
+	"strings"
+	"testing"
+)
+
+var whenTestData = util.StringAnyMap{
+	"Yes":      true,
+	"No":       false,
+	"Empty":    "",
+	"Name":     "cpp",
+	"Includes": []string{"QObject", "QString"},
+	"Version":  "6.10.1",
+	"Depth":    3,
+	"NoItems":  []any{},
+	"Items":    []any{"a"},
+	"NoValues": map[string]any{},
+}
+
+func TestEvalWhenExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// values alone
+		{"Yes", true},
+		{"No", false},
+		{"Empty", false},
+		{"Name", true},
+		{".Yes", true},
+		{"true", true},
+		{"Includes", true},
+		{"NoItems", false},
+		{"Items", true},
+		{"NoValues", false},
+		{"not NoItems and not NoValues", true},
+
+		// precedence
+		{`not Name == "x"`, true},
+		{`not Name == "cpp"`, false},
+		{"Yes or No and No", true},
+		{"(Yes or No) and No", false},
+		{"not Yes or Yes", true},
+		{"not (Yes or Yes)", false},
+		{"!No && Yes || No", true},
+
+		// comparisons
+		{`Name == "cpp"`, true},
+		{`Name != 'cpp'`, false},
+		{"Depth > 2", true},
+		{"Depth <= 2", false},
+		{"Depth > -1", true},
+		{`"a" < "b"`, true},
+		{"No == false", true},
+		{"Empty == false", true},
+
+		// lists
+		{`"QObject" in Includes`, true},
+		{`"QWidget" in Includes`, false},
+		{`"QWidget" not in Includes`, true},
+		{`"QObject" not in Includes`, false},
+		{`Name in ["cpp", "python"]`, true},
+		{`Name in []`, false},
+
+		// versions
+		{"6.10 > 6.9", true},
+		{"6.5 == 6.5.0", true},
+		{"Version >= 6.10", true},
+		{"Version < 6.9", false},
+		{"Version >= v6.5", true},
+		{`Version == "v6.10.1"`, true},
+		{"6 < 6.1", true},
+	}
+
+	for _, test := range tests {
+		got, err := EvalWhenExpr(test.expr, whenTestData)
+		if err != nil {
+			t.Errorf("%v: unexpected error, %v", test.expr, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("%v: got %v, want %v", test.expr, got, test.want)
+		}
+	}
+}
+
+func TestEvalWhenExprErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"Missing", "cannot find field, given = 'Missing'"},
+		{"Yes and", "unexpected end"},
+		{"(Yes", "expected ')'"},
+		{"Yes Yes", "unexpected 'Yes'"},
+		{"Name >", "unexpected end"},
+		{`"open`, "unterminated string"},
+		{"Yes not Yes", "expected 'in' after 'not'"},
+		{"Name in Name", "not a list"},
+		{"Includes < 6", "cannot compare values"},
+		{"Depth > -1.5", "negative version"},
+		{"Yes # No", "unexpected '#'"},
+	}
+
+	for _, test := range tests {
+		_, err := EvalWhenExpr(test.expr, whenTestData)
+		if err == nil {
+			t.Errorf("%v: expected an error", test.expr)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got '%v', want '%v'", test.expr, err, test.want)
+		}
+	}
+}
+
+func TestIsWhenTemplate(t *testing.T) {
+	tests := []struct {
+		when string
+		want bool
+	}{
+		{"{{ not .qArgPytest }}", true},
+		{`{{ qContains .qArgInclude "QObject" }}`, true},
+		{"not qArgPytest", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := IsWhenTemplate(test.when); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.when, got, test.want)
+		}
+	}
+}
+
+// conditions written as templates keep working as before
+func TestEvalWhenConditionTemplate(t *testing.T) {
+	g := &Generator{}
+	g.GlobalContext.Funcs = createGeneralFuncMap(nil)
+	g.GlobalContext.Data = util.StringAnyMap{
+		"qArgPytest":  true,
+		"qArgInclude": []string{"QObject"},
+	}
+
+	tests := []struct {
+		when string
+		want bool
+	}{
+		{"", true},
+		{"{{ .qArgPytest }}", true},
+		{"{{ not .qArgPytest }}", false},
+		{`{{ qContains .qArgInclude "QObject" }}`, true},
+		{`{{ qContains .qArgInclude "QWidget" }}`, false},
+		{"qArgPytest", true},
+		{"not qArgPytest", false},
+	}
+
+	for _, test := range tests {
+		got, err := g.evalWhenCondition(ConfigEntryFile{When: test.when})
+		if err != nil {
+			t.Errorf("%v: unexpected error, %v", test.when, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("%v: got %v, want %v", test.when, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"qtcli/cmake"
+	"slices"
+	"strings"
+)
+
+// returns the topmost CMakeLists.txt in a row of directories
+// containing one, starting from the nearest one
+func findTopListFile(startDir string) (string, error) {
+	path, err := cmake.FindListFile(startDir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		parent := filepath.Join(
+			filepath.Dir(filepath.Dir(path)), cmake.ListFileName)
+		if parent == path {
+			return path, nil
+		}
+
+		if _, err := os.Stat(parent); err != nil {
+			return path, nil
+		}
+
+		path = parent
+	}
+}
+
+// reads the given CMakeLists.txt and ones in subdirectories
+func readCMakeProject(path string) (*Info, error) {
+	listFiles := []*cmake.ListFile{}
+	if err := collectListFiles(path, &listFiles); err != nil {
+		return nil, err
+	}
+
+	info := &Info{
+		BuildSystem: BuildSystemCMake,
+		ProjectFile: path,
+		Targets:     []Target{},
+		QmlModules:  []QmlModule{},
+	}
+
+	// targets defined by commands, then by qt_add_qml_module()
+	for _, l := range listFiles {
+		for _, name := range l.Targets() {
+			if info.findTarget(name) == nil {
+				info.Targets = append(info.Targets,
+					newTarget(name, l.TargetType(name), l.Path))
+			}
+		}
+	}
+
+	for _, l := range listFiles {
+		for _, module := range l.QmlModules() {
+			if info.findTarget(module.Target) == nil {
+				info.Targets = append(info.Targets,
+					newTarget(module.Target, "library", l.Path))
+			}
+
+			info.QmlModules = append(info.QmlModules, QmlModule{
+				URI:      module.URI,
+				Version:  module.Version,
+				Target:   module.Target,
+				File:     l.Path,
+				QmlFiles: module.QmlFiles,
+			})
+		}
+	}
+
+	for index := range info.Targets {
+		target := &info.Targets[index]
+		targetDir := filepath.Dir(target.File)
+
+		for _, l := range listFiles {
+			for _, source := range l.TargetSources(target.Name) {
+				target.addSources([]string{
+					rebasePath(source, l.Dir(), targetDir)})
+			}
+
+			target.addQtModules(l.LinkedQtModules(target.Name))
+		}
+	}
+
+	for _, l := range listFiles {
+		if version := l.QtMajorVersion(); version != 0 {
+			info.QtMajorVersion = version
+			break
+		}
+	}
+
+	return info, nil
+}
+
+func collectListFiles(path string, all *[]*cmake.ListFile) error {
+	if slices.ContainsFunc(*all, func(l *cmake.ListFile) bool {
+		return l.Path == path
+	}) {
+		return nil
+	}
+
+	l, err := cmake.ReadListFile(path)
+	if err != nil {
+		return err
+	}
+
+	*all = append(*all, l)
+
+	for _, dir := range l.Subdirectories() {
+		// e.g., add_subdirectory(${PROJECT_SOURCE_DIR}/3rdparty)
+		if strings.Contains(dir, "$") {
+			continue
+		}
+
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(l.Dir(), dir)
+		}
+
+		sub := filepath.Join(dir, cmake.ListFileName)
+		if _, err := os.Stat(sub); err != nil {
+			continue
+		}
+
+		if err := collectListFiles(sub, all); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newTarget(name string, targetType string, file string) Target {
+	return Target{
+		Name:      name,
+		Type:      targetType,
+		File:      file,
+		Sources:   []string{},
+		QtModules: []string{},
+	}
+}
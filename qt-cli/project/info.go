@@ -0,0 +1,102 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"qtcli/cmake"
+	"slices"
+	"strings"
+)
+
+const (
+	BuildSystemCMake = "cmake"
+	BuildSystemQMake = "qmake"
+)
+
+// note,
+// file paths are absolute, while sources are relative to the directory
+// of the file defining the target, as written there.
+type Info struct {
+	BuildSystem    string      `json:"buildSystem"`
+	ProjectFile    string      `json:"projectFile"`
+	QtMajorVersion int         `json:"qtMajorVersion,omitempty"`
+	Targets        []Target    `json:"targets"`
+	QmlModules     []QmlModule `json:"qmlModules"`
+}
+
+type Target struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	File      string   `json:"file"`
+	Sources   []string `json:"sources"`
+	QtModules []string `json:"qtModules"`
+}
+
+type QmlModule struct {
+	URI      string   `json:"uri"`
+	Version  string   `json:"version,omitempty"`
+	Target   string   `json:"target"`
+	File     string   `json:"file"`
+	QmlFiles []string `json:"qmlFiles"`
+}
+
+// reads the project containing the given directory. a CMake project
+// is preferred to a qmake project if both are found.
+func Read(startDir string) (*Info, error) {
+	if path, err := findTopListFile(startDir); err == nil {
+		return readCMakeProject(path)
+	}
+
+	if path, err := findTopProFile(startDir); err == nil {
+		return readQMakeProject(path)
+	}
+
+	return nil, fmt.Errorf(
+		"cannot find %v or .pro file from %v upward",
+		cmake.ListFileName, startDir)
+}
+
+func (info *Info) findTarget(name string) *Target {
+	for index := range info.Targets {
+		if info.Targets[index].Name == name {
+			return &info.Targets[index]
+		}
+	}
+
+	return nil
+}
+
+func (t *Target) addSources(values []string) {
+	for _, value := range values {
+		if !slices.Contains(t.Sources, value) {
+			t.Sources = append(t.Sources, value)
+		}
+	}
+}
+
+func (t *Target) addQtModules(values []string) {
+	for _, value := range values {
+		if !slices.Contains(t.QtModules, value) {
+			t.QtModules = append(t.QtModules, value)
+		}
+	}
+}
+
+// makes a path written in the file at fromDir relative to toDir.
+// paths with variables are kept as they are.
+func rebasePath(path string, fromDir string, toDir string) string {
+	if fromDir == toDir || filepath.IsAbs(path) ||
+		strings.Contains(path, "$") {
+		return path
+	}
+
+	rel, err := filepath.Rel(toDir, filepath.Join(fromDir, path))
+	if err != nil {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
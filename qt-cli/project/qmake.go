@@ -0,0 +1,242 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"qtcli/qmake"
+	"slices"
+	"strings"
+)
+
+// modules qmake adds unless removed, i.e., QT = core gui
+var defaultQMakeModules = []string{"core", "gui"}
+
+// walks up to the nearest directory containing a .pro file, then climbs
+// while the parent directory has a subdirs project
+func findTopProFile(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", err
+	}
+
+	path := ""
+	for len(path) == 0 {
+		path, err = pickProFile(dir)
+		if err != nil {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if len(path) == 0 && parent == dir {
+			return "", fmt.Errorf(
+				"cannot find .pro file from %v upward", startDir)
+		}
+
+		dir = parent
+	}
+
+	for {
+		parent := filepath.Dir(filepath.Dir(path))
+		candidate, err := pickProFile(parent)
+		if err != nil || len(candidate) == 0 || candidate == path {
+			return path, nil
+		}
+
+		p, err := qmake.ReadProjectFile(candidate)
+		if err != nil || !isSubdirsProject(p) {
+			return path, nil
+		}
+
+		path = candidate
+	}
+}
+
+func pickProFile(dir string) (string, error) {
+	candidates, err := filepath.Glob(filepath.Join(dir, "*.pro"))
+	if err != nil {
+		return "", err
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", nil
+
+	case 1:
+		return candidates[0], nil
+	}
+
+	preferred := filepath.Join(dir, filepath.Base(dir)+".pro")
+	if slices.Contains(candidates, preferred) {
+		return preferred, nil
+	}
+
+	return "", fmt.Errorf(
+		"multiple project files found in %v, %v",
+		dir, strings.Join(candidates, ", "))
+}
+
+// reads the given .pro file and ones listed in SUBDIRS
+func readQMakeProject(path string) (*Info, error) {
+	info := &Info{
+		BuildSystem: BuildSystemQMake,
+		ProjectFile: path,
+		Targets:     []Target{},
+		QmlModules:  []QmlModule{},
+	}
+
+	visited := []string{}
+	if err := readQMakeFile(info, path, &visited); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func readQMakeFile(info *Info, path string, visited *[]string) error {
+	if slices.Contains(*visited, path) {
+		return nil
+	}
+
+	*visited = append(*visited, path)
+
+	p, err := qmake.ReadProjectFile(path)
+	if err != nil {
+		return err
+	}
+
+	if isSubdirsProject(p) {
+		for _, sub := range p.Evaluate("SUBDIRS", []string{}) {
+			subPath := findSubdirProFile(p.Dir(), sub)
+			if len(subPath) == 0 {
+				continue
+			}
+
+			if err := readQMakeFile(info, subPath, visited); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	files := []*qmake.ProjectFile{}
+	collectIncludes(p, &files)
+
+	evaluate := func(variable string, initial []string) []string {
+		values := initial
+		for _, file := range files {
+			values = file.Evaluate(variable, values)
+		}
+
+		return values
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name := baseName
+	if values := evaluate("TARGET", []string{}); len(values) != 0 {
+		name = values[len(values)-1]
+	}
+
+	targetType := "executable"
+	if slices.Contains(evaluate("TEMPLATE", []string{"app"}), "lib") {
+		targetType = "library"
+	}
+
+	target := newTarget(name, targetType, path)
+
+	for _, file := range files {
+		for _, variable := range qmake.FileVariables {
+			for _, value := range file.Evaluate(variable, []string{}) {
+				target.addSources([]string{
+					rebasePath(value, file.Dir(), p.Dir())})
+			}
+		}
+	}
+
+	for _, module := range evaluate("QT", defaultQMakeModules) {
+		target.addQtModules([]string{qmake.ComponentName(module)})
+	}
+
+	info.Targets = append(info.Targets, target)
+
+	// CONFIG += qmltypes
+	uri := evaluate("QML_IMPORT_NAME", []string{})
+	if len(uri) != 0 {
+		module := QmlModule{
+			URI:      uri[0],
+			Target:   name,
+			File:     path,
+			QmlFiles: []string{},
+		}
+
+		major := evaluate("QML_IMPORT_MAJOR_VERSION", []string{})
+		if len(major) != 0 {
+			module.Version = major[0]
+
+			minor := evaluate("QML_IMPORT_MINOR_VERSION", []string{})
+			if len(minor) != 0 {
+				module.Version += "." + minor[0]
+			}
+		}
+
+		info.QmlModules = append(info.QmlModules, module)
+	}
+
+	return nil
+}
+
+// returns the file itself followed by .pri files it includes
+func collectIncludes(p *qmake.ProjectFile, all *[]*qmake.ProjectFile) {
+	for _, file := range *all {
+		if file.Path == p.Path {
+			return
+		}
+	}
+
+	*all = append(*all, p)
+
+	for _, include := range p.Includes() {
+		if strings.Contains(include, "$") {
+			continue
+		}
+
+		path := include
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(p.Dir(), path)
+		}
+
+		sub, err := qmake.ReadProjectFile(path)
+		if err != nil {
+			continue
+		}
+
+		collectIncludes(sub, all)
+	}
+}
+
+// e.g., SUBDIRS += app -> app/app.pro, SUBDIRS += lib/lib.pro
+func findSubdirProFile(dir string, sub string) string {
+	if strings.Contains(sub, "$") {
+		return ""
+	}
+
+	path := filepath.Join(dir, sub)
+	if strings.HasSuffix(sub, ".pro") {
+		return path
+	}
+
+	path = filepath.Join(path, filepath.Base(sub)+".pro")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+
+	return path
+}
+
+func isSubdirsProject(p *qmake.ProjectFile) bool {
+	return slices.Contains(p.Evaluate("TEMPLATE", []string{}), "subdirs")
+}
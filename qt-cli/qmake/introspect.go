@@ -0,0 +1,151 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package qmake
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// variables listing files which belong to a target
+var FileVariables = []string{
+	"HEADERS", "SOURCES", "FORMS", "RESOURCES", "DISTFILES", "OTHER_FILES",
+}
+
+// note,
+// qmake module names are lowercase. names which cannot be derived
+// by capitalizing the first letter are listed here.
+var componentNames = map[string]string{
+	"3danimation":         "3DAnimation",
+	"3dcore":              "3DCore",
+	"3dextras":            "3DExtras",
+	"3dinput":             "3DInput",
+	"3dlogic":             "3DLogic",
+	"3drender":            "3DRender",
+	"core5compat":         "Core5Compat",
+	"datavisualization":   "DataVisualization",
+	"dbus":                "DBus",
+	"httpserver":          "HttpServer",
+	"linguisttools":       "LinguistTools",
+	"multimediawidgets":   "MultimediaWidgets",
+	"networkauth":         "NetworkAuth",
+	"opengl":              "OpenGL",
+	"openglwidgets":       "OpenGLWidgets",
+	"pdfwidgets":          "PdfWidgets",
+	"printsupport":        "PrintSupport",
+	"qmlmodels":           "QmlModels",
+	"quick3d":             "Quick3D",
+	"quickcontrols2":      "QuickControls2",
+	"quickcontrols2basic": "QuickControls2Basic",
+	"quickcontrols2impl":  "QuickControls2Impl",
+	"quickdialogs2":       "QuickDialogs2",
+	"quickeffects":        "QuickEffects",
+	"quicklayouts":        "QuickLayouts",
+	"quickparticles":      "QuickParticles",
+	"quickshapes":         "QuickShapes",
+	"quicktemplates2":     "QuickTemplates2",
+	"quicktest":           "QuickTest",
+	"quicktimeline":       "QuickTimeline",
+	"quickwidgets":        "QuickWidgets",
+	"remoteobjects":       "RemoteObjects",
+	"serialbus":           "SerialBus",
+	"serialport":          "SerialPort",
+	"shadertools":         "ShaderTools",
+	"spatialaudio":        "SpatialAudio",
+	"statemachine":        "StateMachine",
+	"svgwidgets":          "SvgWidgets",
+	"testlib":             "Test",
+	"texttospeech":        "TextToSpeech",
+	"uitools":             "UiTools",
+	"virtualkeyboard":     "VirtualKeyboard",
+	"waylandclient":       "WaylandClient",
+	"waylandcompositor":   "WaylandCompositor",
+	"webchannel":          "WebChannel",
+	"webengine":           "WebEngine",
+	"webenginecore":       "WebEngineCore",
+	"webenginequick":      "WebEngineQuick",
+	"webenginewidgets":    "WebEngineWidgets",
+	"websockets":          "WebSockets",
+	"webview":             "WebView",
+}
+
+var includeRegex = regexp.MustCompile(`^\s*include\s*\(\s*"?([^")]+)"?\s*\)`)
+
+// applies unscoped assignments to the variable in order, starting from
+// the given values, e.g., ones set by a file including this one.
+// quotes and a leading $$PWD/ are removed.
+func (p *ProjectFile) Evaluate(variable string, initial []string) []string {
+	all := slices.Clone(initial)
+
+	for _, a := range p.FindAssignments(variable) {
+		values := []string{}
+		for _, value := range a.Values {
+			value = strings.Trim(value, `"`)
+			value = strings.TrimPrefix(value, "$$PWD/")
+			value = strings.TrimPrefix(value, "$${PWD}/")
+			values = append(values, value)
+		}
+
+		switch a.Operator {
+		case "=":
+			all = values
+
+		case "+=":
+			all = append(all, values...)
+
+		case "*=":
+			for _, value := range values {
+				if !slices.Contains(all, value) {
+					all = append(all, value)
+				}
+			}
+
+		case "-=":
+			all = slices.DeleteFunc(all, func(value string) bool {
+				return slices.Contains(values, value)
+			})
+		}
+	}
+
+	return all
+}
+
+// returns files given to include() outside of scopes
+func (p *ProjectFile) Includes() []string {
+	all := []string{}
+	depth := 0
+
+	for _, line := range strings.Split(p.Source, "\n") {
+		line = stripComment(line)
+		if depth == 0 {
+			if matches := includeRegex.FindStringSubmatch(line); matches != nil {
+				path := strings.TrimPrefix(matches[1], "$$PWD/")
+				all = append(all, strings.TrimSpace(path))
+			}
+		}
+
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth < 0 {
+			depth = 0
+		}
+	}
+
+	return all
+}
+
+// returns the CMake component name of a qmake module name,
+// e.g., widgets -> Widgets, quickcontrols2 -> QuickControls2
+func ComponentName(module string) string {
+	module = strings.TrimSuffix(module, "-private")
+	if name, found := componentNames[module]; found {
+		return name
+	}
+
+	if len(module) == 0 {
+		return module
+	}
+
+	return strings.ToUpper(module[:1]) + module[1:]
+}
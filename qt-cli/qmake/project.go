@@ -0,0 +1,328 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package qmake
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type ProjectFile struct {
+	Path        string
+	Source      string
+	Assignments []Assignment
+}
+
+// note,
+// a logical line may span several physical lines joined by '\'.
+// offsets point into the original source.
+type Assignment struct {
+	Variable string
+	Operator string
+	Values   []string
+	Start    int
+	End      int
+	Line     int
+	ValueEnd int
+	Indent   string
+	Scoped   bool
+}
+
+var assignmentRegex = regexp.MustCompile(
+	`^\s*([A-Za-z_][A-Za-z0-9_.]*)\s*(\+=|\*=|-=|~=|=)(.*)$`)
+
+var variableByExtension = map[string]string{
+	".h":   "HEADERS",
+	".hh":  "HEADERS",
+	".hpp": "HEADERS",
+	".hxx": "HEADERS",
+	".c":   "SOURCES",
+	".cc":  "SOURCES",
+	".cpp": "SOURCES",
+	".cxx": "SOURCES",
+	".ui":  "FORMS",
+	".qrc": "RESOURCES",
+}
+
+// walks up from the given directory until a directory containing
+// .pro or .pri files is found. if there are several, a .pri file is
+// preferred to a .pro file, and a file named after the directory
+// is preferred to others.
+func FindProjectFile(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path, err := pickProjectFile(dir)
+		if err != nil {
+			return "", err
+		}
+
+		if len(path) != 0 {
+			return path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf(
+				"cannot find .pro or .pri file from %v upward", startDir)
+		}
+
+		dir = parent
+	}
+}
+
+func pickProjectFile(dir string) (string, error) {
+	for _, ext := range []string{".pri", ".pro"} {
+		candidates, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+		if err != nil {
+			return "", err
+		}
+
+		switch len(candidates) {
+		case 0:
+			continue
+
+		case 1:
+			return candidates[0], nil
+		}
+
+		preferred := filepath.Join(dir, filepath.Base(dir)+ext)
+		if slices.Contains(candidates, preferred) {
+			return preferred, nil
+		}
+
+		return "", fmt.Errorf(
+			"multiple project files found in %v, %v",
+			dir, strings.Join(candidates, ", "))
+	}
+
+	return "", nil
+}
+
+func ReadProjectFile(path string) (*ProjectFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ProjectFile{Path: path}
+	p.setSource(string(raw))
+	return p, nil
+}
+
+func (p *ProjectFile) Write() error {
+	return os.WriteFile(p.Path, []byte(p.Source), 0666)
+}
+
+func (p *ProjectFile) Dir() string {
+	return filepath.Dir(p.Path)
+}
+
+// returns unscoped assignments to the given variable
+func (p *ProjectFile) FindAssignments(variable string) []Assignment {
+	all := []Assignment{}
+	for _, a := range p.Assignments {
+		if a.Variable == variable && !a.Scoped {
+			all = append(all, a)
+		}
+	}
+
+	return all
+}
+
+// adds the given files to HEADERS, SOURCES, FORMS, RESOURCES or
+// DISTFILES according to their extensions, and returns the ones added.
+// paths are relative to the directory of the project file.
+func (p *ProjectFile) AddFiles(files []string) ([]string, error) {
+	added := []string{}
+
+	for _, file := range files {
+		variable := variableByExtension[strings.ToLower(filepath.Ext(file))]
+		if len(variable) == 0 {
+			variable = "DISTFILES"
+		}
+
+		if p.hasValue(variable, file) {
+			continue
+		}
+
+		p.appendValue(variable, quoteValue(file))
+		added = append(added, file)
+	}
+
+	return added, nil
+}
+
+func (p *ProjectFile) hasValue(variable string, value string) bool {
+	for _, a := range p.FindAssignments(variable) {
+		for _, v := range a.Values {
+			v = strings.Trim(v, `"`)
+			if v == value || v == "$$PWD/"+value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *ProjectFile) appendValue(variable string, value string) {
+	candidates := []Assignment{}
+	for _, a := range p.FindAssignments(variable) {
+		if a.Operator == "=" || a.Operator == "+=" {
+			candidates = append(candidates, a)
+		}
+	}
+
+	if len(candidates) == 0 {
+		text := fmt.Sprintf("%v += \\\n    %v\n", variable, value)
+		if len(p.Source) != 0 {
+			if !strings.HasSuffix(p.Source, "\n") {
+				text = "\n" + text
+			}
+
+			text = "\n" + text
+		}
+
+		p.insert(len(p.Source), text)
+		return
+	}
+
+	last := candidates[len(candidates)-1]
+	if len(last.Indent) != 0 {
+		p.insert(last.ValueEnd, " \\\n"+last.Indent+value)
+	} else {
+		p.insert(last.ValueEnd, " "+value)
+	}
+}
+
+func (p *ProjectFile) insert(offset int, text string) {
+	p.setSource(p.Source[:offset] + text + p.Source[offset:])
+}
+
+func (p *ProjectFile) setSource(source string) {
+	p.Source = source
+	p.Assignments = parseAssignments(source)
+}
+
+func parseAssignments(source string) []Assignment {
+	all := []Assignment{}
+	depth := 0
+	offset := 0
+	line := 1
+
+	for offset < len(source) {
+		start := offset
+		startLine := line
+		physical := []string{}
+		ends := []int{}
+
+		// collect physical lines of a logical line
+		for offset < len(source) {
+			end := strings.IndexByte(source[offset:], '\n')
+			if end < 0 {
+				end = len(source)
+			} else {
+				end += offset
+			}
+
+			text := source[offset:end]
+			physical = append(physical, text)
+			ends = append(ends, offset+len(stripComment(text)))
+			offset = end + 1
+			line++
+
+			if !strings.HasSuffix(
+				strings.TrimRight(stripComment(text), " \t\r"), "\\") {
+				break
+			}
+		}
+
+		logical := ""
+		for _, text := range physical {
+			text = strings.TrimRight(stripComment(text), " \t\r")
+			logical += strings.TrimSuffix(text, "\\") + " "
+		}
+
+		matches := assignmentRegex.FindStringSubmatch(logical)
+		if matches != nil {
+			a := Assignment{
+				Variable: matches[1],
+				Operator: matches[2],
+				Values:   strings.Fields(matches[3]),
+				Start:    start,
+				End:      min(offset, len(source)),
+				Line:     startLine,
+				ValueEnd: trimEnd(source, ends[len(ends)-1]),
+				Scoped:   depth > 0,
+			}
+
+			if len(physical) > 1 {
+				a.Indent = findIndent(physical[len(physical)-1])
+			}
+
+			all = append(all, a)
+		}
+
+		// scopes, e.g., 'win32 {', '} else {'.
+		// single line scopes like 'unix: SOURCES += ...' never match
+		// the assignment pattern, so they are not collected at all.
+		for _, text := range physical {
+			text = stripComment(text)
+			depth += strings.Count(text, "{") - strings.Count(text, "}")
+		}
+
+		if depth < 0 {
+			depth = 0
+		}
+	}
+
+	return all
+}
+
+// removes a comment, except '#' in quotes
+func stripComment(text string) string {
+	quoted := false
+	for i, r := range text {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case '#':
+			if !quoted {
+				return text[:i]
+			}
+		}
+	}
+
+	return text
+}
+
+func trimEnd(source string, offset int) int {
+	for offset > 0 &&
+		(source[offset-1] == ' ' || source[offset-1] == '\t' ||
+			source[offset-1] == '\r') {
+		offset--
+	}
+
+	return offset
+}
+
+func findIndent(text string) string {
+	return text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+}
+
+func quoteValue(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+
+	return value
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// number of unchanged lines shown around a change
+const diffContextLines = 3
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// returns a unified diff between two texts, or an empty string
+// if they are the same
+func UnifiedDiff(name string, oldText string, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %v\n", name)
+	fmt.Fprintf(&b, "+++ %v\n", name)
+
+	for start := 0; start < len(ops); {
+		// skip to the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+
+		if start == len(ops) {
+			break
+		}
+
+		first := max(start-diffContextLines, 0)
+		last := start
+		for index := start; index < len(ops); index++ {
+			if ops[index].kind != ' ' {
+				last = index
+			} else if index-last > 2*diffContextLines {
+				break
+			}
+		}
+
+		end := min(last+diffContextLines+1, len(ops))
+		writeHunk(&b, ops, first, end)
+		start = end
+	}
+
+	return b.String()
+}
+
+func writeHunk(b *strings.Builder, ops []diffOp, first int, end int) {
+	oldLine, newLine := 1, 1
+	for _, op := range ops[:first] {
+		if op.kind != '+' {
+			oldLine++
+		}
+		if op.kind != '-' {
+			newLine++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+	for _, op := range ops[first:end] {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+
+	// note, an empty range refers to the line before it
+	if oldCount == 0 {
+		oldLine--
+	}
+	if newCount == 0 {
+		newLine--
+	}
+
+	fmt.Fprintf(b, "@@ -%v,%v +%v,%v @@\n",
+		oldLine, oldCount, newLine, newCount)
+
+	for _, op := range ops[first:end] {
+		b.WriteByte(op.kind)
+		b.WriteString(op.text)
+		b.WriteByte('\n')
+	}
+}
+
+// returns edit operations based on the longest common subsequence
+func diffLines(a []string, b []string) []diffOp {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	ops := []diffOp{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+
+	return ops
+}
+
+func splitLines(text string) []string {
+	if len(text) == 0 {
+		return []string{}
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
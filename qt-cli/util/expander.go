@@ -5,16 +5,44 @@ package util
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
 	"text/template"
+	"text/template/parse"
 )
 
 type TemplateExpander struct {
-	data  StringAnyMap
-	funcs template.FuncMap
-	name  string
+	data      StringAnyMap
+	funcs     template.FuncMap
+	name      string
+	strict    bool
+	onMissing func(MissingKeyError)
+	sources   []SourceSpan
 }
 
+// a key the template refers to, which is not in the data
+type MissingKeyError struct {
+	Template string
+	Line     int
+	Key      string
+}
+
+func (e MissingKeyError) Error() string {
+	return fmt.Sprintf(
+		"cannot find key in template, given = '%v', template = '%v', line = %v",
+		e.Key, e.Template, e.Line)
+}
+
+// e.g., template: file.h.tmpl:12:5: executing "file.h.tmpl" at <.Foo>:
+// map has no entry for key "Foo"
+var missingKeyRegex = regexp.MustCompile(
+	`^template: (.+?):(\d+):\d+: executing .*: map has no entry for key "(.*)"$`)
+
 func NewTemplateExpander() *TemplateExpander {
 	return &TemplateExpander{
 		data:  StringAnyMap{},
@@ -37,11 +65,104 @@ func (e *TemplateExpander) Funcs(funcs template.FuncMap) *TemplateExpander {
 	return e
 }
 
+// makes a key missing in the data an error, instead of <no value>
+func (e *TemplateExpander) Strict(strict bool) *TemplateExpander {
+	e.strict = strict
+	return e
+}
+
+// reports each key missing in the data, if not strict
+func (e *TemplateExpander) OnMissingKey(
+	onMissing func(MissingKeyError),
+) *TemplateExpander {
+	e.onMissing = onMissing
+	return e
+}
+
+// tells where lines of the template come from, so that errors and
+// missing keys refer to them instead of the template name
+func (e *TemplateExpander) Sources(spans ...SourceSpan) *TemplateExpander {
+	e.sources = spans
+	return e
+}
+
 func (e *TemplateExpander) RunString(templateString string) (string, error) {
-	return e.execTemplate(template.
+	output, err := e.execTemplate(template.
 		New(e.name).
 		Funcs(e.funcs).
 		Parse(templateString))
+	if err != nil {
+		return "", LocateTemplateError(err, templateString, e.sources)
+	}
+
+	return output, nil
+}
+
+// returns the value of a template made of a single pipeline, e.g.,
+// '{{ .qArgAdd }}' -> []string{"Q_OBJECT"}, keeping the type instead of
+// printing it. returns false if the template is anything else, e.g.,
+// '{{ .ClassName }}.h'.
+func (e *TemplateExpander) RunValue(templateString string) (any, bool, error) {
+	tmpl, err := template.New(e.name).Funcs(e.funcs).Parse(templateString)
+	if err != nil {
+		return nil, false, LocateTemplateError(err, templateString, e.sources)
+	}
+
+	pipe := findSinglePipeline(tmpl)
+	if pipe == nil {
+		return nil, false, nil
+	}
+
+	// note, the capture function is added to a copy of the functions
+	var value any
+	funcs := template.FuncMap{}
+	maps.Copy(funcs, e.funcs)
+	funcs[captureFuncName] = func(v any) string {
+		value = v
+		return ""
+	}
+
+	// e.g., {{ qCapture (cpp.CreateIncludes .qArgInclude .qArgAdd) }}
+	capture := fmt.Sprintf("{{ %v (%v) }}", captureFuncName, pipe)
+	_, err = e.execTemplate(template.New(e.name).Funcs(funcs).Parse(capture))
+	if err != nil {
+		return nil, false, LocateTemplateError(err, templateString, e.sources)
+	}
+
+	return value, true, nil
+}
+
+const captureFuncName = "qCaptureValue"
+
+// returns the pipeline of a template with a single action and no text
+// other than spaces, without declarations of variables
+func findSinglePipeline(tmpl *template.Template) *parse.PipeNode {
+	if tmpl.Tree == nil || len(tmpl.Templates()) != 1 {
+		return nil
+	}
+
+	var pipe *parse.PipeNode
+
+	for _, node := range tmpl.Tree.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			if len(strings.TrimSpace(string(n.Text))) != 0 {
+				return nil
+			}
+
+		case *parse.ActionNode:
+			if pipe != nil || len(n.Pipe.Decl) != 0 {
+				return nil
+			}
+
+			pipe = n.Pipe
+
+		default:
+			return nil
+		}
+	}
+
+	return pipe
 }
 
 func (e *TemplateExpander) RunFile(filePath string) (string, error) {
@@ -59,12 +180,103 @@ func (e *TemplateExpander) execTemplate(
 		return "", err
 	}
 
+	if e.strict {
+		tmpl.Option("missingkey=error")
+	} else if e.onMissing != nil {
+		if err := e.findMissingKeys(tmpl); err != nil {
+			return "", err
+		}
+	}
+
+	output, err := e.execute(tmpl, e.data)
+	if err != nil {
+		if missing, ok := asMissingKeyError(err); ok {
+			return "", missing
+		}
+
+		return "", err
+	}
+
+	return output, nil
+}
+
+func (e *TemplateExpander) execute(
+	tmpl *template.Template,
+	data StringAnyMap,
+) (string, error) {
 	var buffer bytes.Buffer
 	var io io.Writer = &buffer
-	err = tmpl.Execute(io, e.data)
+	err := tmpl.Execute(io, data)
 	if err != nil {
 		return "", err
 	}
 
 	return buffer.String(), nil
 }
+
+// executes the template until no key is missing, adding each missing key
+// to a copy of the data as an empty value, which renders the same as
+// a missing one
+func (e *TemplateExpander) findMissingKeys(tmpl *template.Template) error {
+	probe, err := tmpl.Clone()
+	if err != nil {
+		return err
+	}
+
+	probe.Option("missingkey=error")
+	data := StringAnyMap{}
+	maps.Copy(data, e.data)
+
+	for {
+		_, err := e.execute(probe, data)
+		if err == nil {
+			return nil
+		}
+
+		missing, ok := asMissingKeyError(err)
+		if !ok {
+			// note, reported by the actual execution
+			return nil
+		}
+
+		e.onMissing(e.locateMissingKey(missing))
+
+		// e.g., a key of a nested map, which cannot be added
+		if _, found := data[missing.Key]; found {
+			return nil
+		}
+
+		data[missing.Key] = nil
+	}
+}
+
+func (e *TemplateExpander) locateMissingKey(
+	missing MissingKeyError,
+) MissingKeyError {
+	if path, line, found := FindSourceLine(e.sources, missing.Line); found {
+		missing.Template = path
+		missing.Line = line
+	}
+
+	return missing
+}
+
+func asMissingKeyError(err error) (MissingKeyError, bool) {
+	var execErr template.ExecError
+	if !errors.As(err, &execErr) {
+		return MissingKeyError{}, false
+	}
+
+	matches := missingKeyRegex.FindStringSubmatch(execErr.Error())
+	if matches == nil {
+		return MissingKeyError{}, false
+	}
+
+	line, _ := strconv.Atoi(matches[2])
+
+	return MissingKeyError{
+		Template: matches[1],
+		Line:     line,
+		Key:      matches[3],
+	}, true
+}
@@ -0,0 +1,232 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type GitIdentity struct {
+	Name         string
+	Email        string
+	Organization string
+}
+
+// reads user.name, user.email and user.organization from the global
+// git config files and the config of the repository containing the
+// directory, where the repository one takes precedence. missing or
+// unreadable files are skipped.
+//
+// note,
+// includes and conditional includes are not followed.
+func ReadGitIdentity(dir string) GitIdentity {
+	identity := GitIdentity{}
+
+	for _, path := range gitConfigPaths(dir) {
+		values := readGitConfigSection(path, "user")
+
+		if value, found := values["name"]; found {
+			identity.Name = value
+		}
+
+		if value, found := values["email"]; found {
+			identity.Email = value
+		}
+
+		if value, found := values["organization"]; found {
+			identity.Organization = value
+		}
+	}
+
+	return identity
+}
+
+// returns paths from the lowest precedence
+func gitConfigPaths(dir string) []string {
+	all := []string{}
+
+	xdgDir := os.Getenv("XDG_CONFIG_HOME")
+	home, err := os.UserHomeDir()
+	if err == nil {
+		if len(xdgDir) == 0 {
+			xdgDir = filepath.Join(home, ".config")
+		}
+
+		all = append(all,
+			filepath.Join(xdgDir, "git", "config"),
+			filepath.Join(home, ".gitconfig"))
+	} else if len(xdgDir) != 0 {
+		all = append(all, filepath.Join(xdgDir, "git", "config"))
+	}
+
+	if gitDir := findGitDir(dir); len(gitDir) != 0 {
+		all = append(all, filepath.Join(gitDir, "config"))
+	}
+
+	return all
+}
+
+// returns the top directory of the work tree containing the directory,
+// or an empty string if it is not in a git repository
+func FindRepositoryRoot(startDir string) string {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// returns the .git directory of the repository. in a worktree or
+// a submodule, .git is a file pointing to the actual directory.
+func findGitDir(startDir string) string {
+	root := FindRepositoryRoot(startDir)
+	if len(root) == 0 {
+		return ""
+	}
+
+	path := filepath.Join(root, ".git")
+	stat, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+
+	if stat.IsDir() {
+		return path
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	gitDir, found := strings.CutPrefix(
+		strings.TrimSpace(string(raw)), "gitdir:")
+	if !found {
+		return ""
+	}
+
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(root, gitDir)
+	}
+
+	// e.g., .git/worktrees/<name> shares the config of .git
+	if common, err := os.ReadFile(
+		filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+
+		return filepath.Clean(commonDir)
+	}
+
+	return gitDir
+}
+
+// returns keys, lowercased, and values of the section without
+// a subsection, e.g., [user] but not [user "work"]
+func readGitConfigSection(path string, section string) map[string]string {
+	values := map[string]string{}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	inSection := false
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.Index(line, "]")
+			if end < 0 {
+				inSection = false
+				continue
+			}
+
+			name := strings.TrimSpace(line[1:end])
+			inSection = strings.EqualFold(name, section)
+
+			// e.g., [user] name = Jane
+			line = strings.TrimSpace(line[end+1:])
+			if len(line) == 0 {
+				continue
+			}
+		}
+
+		if !inSection {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		values[key] = parseGitConfigValue(value)
+	}
+
+	return values
+}
+
+// removes comments and quotes, e.g., "Jane Doe" # work -> Jane Doe
+func parseGitConfigValue(value string) string {
+	var b strings.Builder
+
+	inQuotes := false
+	escaped := false
+
+	for _, c := range strings.TrimSpace(value) {
+		switch {
+		case escaped:
+			switch c {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			default:
+				b.WriteRune(c)
+			}
+
+			escaped = false
+
+		case c == '\\':
+			escaped = true
+
+		case c == '"':
+			inQuotes = !inQuotes
+
+		case (c == '#' || c == ';') && !inQuotes:
+			return strings.TrimSpace(b.String())
+
+		default:
+			b.WriteRune(c)
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
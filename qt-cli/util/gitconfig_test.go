@@ -0,0 +1,114 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGitConfigValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Jane Doe", "Jane Doe"},
+		{"  Jane Doe  ", "Jane Doe"},
+		{`"Jane Doe"`, "Jane Doe"},
+		{`"Jane # Doe"`, "Jane # Doe"},
+		{"Jane Doe # work", "Jane Doe"},
+		{"Jane Doe ; work", "Jane Doe"},
+		{`Jane \"JD\" Doe`, `Jane "JD" Doe`},
+		{`"a\tb\nc"`, "a\tb\nc"},
+		{`C:\\Users`, `C:\Users`},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := parseGitConfigValue(test.value); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestReadGitConfigSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	contents := `# comment
+[core]
+	name = Not This
+[user "work"]
+	name = Not This Either
+[User]
+	Name = "Jane Doe" ; personal
+	email = jane@example.com
+	organization
+[user] organization = Example Ltd.
+`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readGitConfigSection(path, "user")
+	want := map[string]string{
+		"name":         "Jane Doe",
+		"email":        "jane@example.com",
+		"organization": "Example Ltd.",
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%v: got %q, want %q", key, got[key], value)
+		}
+	}
+
+	missing := readGitConfigSection(filepath.Join(t.TempDir(), "none"), "user")
+	if len(missing) != 0 {
+		t.Errorf("missing file: got %v, want none", missing)
+	}
+}
+
+func TestReadGitIdentity(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	global := "[user]\n\tname = Global Name\n\temail = global@example.com\n"
+	err := os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(global), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := filepath.Join(t.TempDir(), "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	local := "[user]\n\tname = Local Name\n\torganization = Example\n"
+	err = os.WriteFile(
+		filepath.Join(repo, ".git", "config"), []byte(local), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subDir := filepath.Join(repo, "src")
+	if err := os.MkdirAll(subDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ReadGitIdentity(subDir)
+	want := GitIdentity{
+		Name:         "Local Name",
+		Email:        "global@example.com",
+		Organization: "Example",
+	}
+
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"path"
+	"strings"
+)
+
+// reports whether the slash-separated path matches the pattern. a pattern
+// without a slash matches the base name, e.g., *.cpp, and ** matches any
+// number of directories, e.g., src/**/*.h or build/**.
+func MatchGlob(pattern string, name string) bool {
+	pattern = strings.Trim(pattern, "/")
+	name = strings.Trim(name, "/")
+
+	if !strings.Contains(pattern, "/") && pattern != "**" {
+		matched, _ := path.Match(pattern, path.Base(name))
+		return matched
+	}
+
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(patterns []string, names []string) bool {
+	for len(patterns) != 0 {
+		if patterns[0] == "**" {
+			for i := 0; i <= len(names); i++ {
+				if matchSegments(patterns[1:], names[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(names) == 0 {
+			return false
+		}
+
+		if matched, _ := path.Match(patterns[0], names[0]); !matched {
+			return false
+		}
+
+		patterns = patterns[1:]
+		names = names[1:]
+	}
+
+	return len(names) == 0
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		// without a slash, the base name is matched
+		{"*.cpp", "main.cpp", true},
+		{"*.cpp", "src/core/main.cpp", true},
+		{"*.cpp", "main.h", false},
+		{"main.*", "src/main.h", true},
+
+		// with a slash, the whole path is matched
+		{"src/*.cpp", "src/main.cpp", true},
+		{"src/*.cpp", "src/core/main.cpp", false},
+		{"/src/*.cpp/", "src/main.cpp", true},
+
+		// ** matches any number of directories
+		{"**", "a/b/c.cpp", true},
+		{"src/**/*.h", "src/a.h", true},
+		{"src/**/*.h", "src/a/b/c.h", true},
+		{"src/**/*.h", "lib/a.h", false},
+		{"build/**", "build/a/b.o", true},
+		{"build/**", "build", true},
+		{"build/**", "src/build/a.o", false},
+		{"**/build/**", "src/build/a.o", true},
+		{"**/test_*.py", "a/b/test_x.py", true},
+		{"**/test_*.py", "a/b/x.py", false},
+	}
+
+	for _, test := range tests {
+		if got := MatchGlob(test.pattern, test.name); got != test.want {
+			t.Errorf("MatchGlob(%v, %v): got %v, want %v",
+				test.pattern, test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2024 The Qt Company Ltd.
+// SPDX-License-Identifier: LicenseRef-Qt-Commercial OR LGPL-3.0-only
+
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// where lines of a template come from, e.g., a header in config.yml
+// followed by the body in a .tmpl file
+type SourceSpan struct {
+	Path string
+
+	// line in the file of the first line of the span
+	Line int
+
+	// number of lines of the span, or 0 for the rest of the template
+	Lines int
+}
+
+// an error at a line of a source file, with the line as an excerpt
+type SourceError struct {
+	Path    string
+	Line    int
+	Message string
+	Excerpt string
+}
+
+func (e SourceError) Error() string {
+	if len(e.Excerpt) == 0 {
+		return fmt.Sprintf("%v:%v: %v", e.Path, e.Line, e.Message)
+	}
+
+	return fmt.Sprintf("%v:%v: %v\n%6d | %v",
+		e.Path, e.Line, e.Message, e.Line, e.Excerpt)
+}
+
+// e.g.,
+// template: file.h.tmpl:12: function "foo" not defined
+// template: file.h.tmpl:12:5: executing "file.h.tmpl" at <.Foo>: ...
+var templateErrorRegex = regexp.MustCompile(
+	`^template: (.+?):(\d+):(?:\d+:)? (?:executing ".*?" )?((?s).*)$`)
+
+// returns the file and the line in it of a line of the template
+func FindSourceLine(spans []SourceSpan, line int) (string, int, bool) {
+	first := 1
+
+	for index, span := range spans {
+		last := index == len(spans)-1
+		if line < first+span.Lines || span.Lines == 0 || last {
+			return span.Path, span.Line + line - first, true
+		}
+
+		first += span.Lines
+	}
+
+	return "", 0, false
+}
+
+// maps a parse or execution error of the template text to the source
+// line it comes from, or returns the error as it is if it cannot
+func LocateTemplateError(err error, text string, spans []SourceSpan) error {
+	if err == nil || len(spans) == 0 {
+		return err
+	}
+
+	if missing, ok := err.(MissingKeyError); ok {
+		path, line, found := FindSourceLine(spans, missing.Line)
+		if !found {
+			return err
+		}
+
+		return SourceError{
+			Path:    path,
+			Line:    line,
+			Message: fmt.Sprintf("cannot find key, given = '%v'", missing.Key),
+			Excerpt: excerptLine(text, missing.Line),
+		}
+	}
+
+	matches := templateErrorRegex.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return err
+	}
+
+	textLine, _ := strconv.Atoi(matches[2])
+	path, line, found := FindSourceLine(spans, textLine)
+	if !found {
+		return err
+	}
+
+	return SourceError{
+		Path:    path,
+		Line:    line,
+		Message: matches[3],
+		Excerpt: excerptLine(text, textLine),
+	}
+}
+
+func excerptLine(text string, line int) string {
+	lines := strings.Split(text, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	return strings.TrimRight(lines[line-1], " \t\r")
+}
@@ -55,6 +55,22 @@ func WriteAll(data []byte, destPath string) (int, error) {
 	return destFile.Write(data)
 }
 
+func AppendAll(data []byte, destPath string) (int, error) {
+	dir := filepath.Dir(destPath)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return 0, err
+	}
+
+	destFile, err := os.OpenFile(
+		destPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return 0, err
+	}
+
+	defer destFile.Close()
+	return destFile.Write(data)
+}
+
 func PrintlnWithName(data string, fileName string) {
 	fmt.Println(">>>>>>>", fileName)
 	fmt.Print(data)